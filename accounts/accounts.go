@@ -19,6 +19,8 @@ type AccountsClient struct {
 }
 
 // ETHBalanceRequest contains the request parameters for GetETHBalance.
+// Etherscan only accepts the latest, earliest and pending tags: use
+// GetHistoricalETHBalance for the balance at a block number.
 type ETHBalanceRequest struct {
 	Address common.Address
 	Tag     ecommon.BlockParameter
@@ -43,6 +45,8 @@ func (c *AccountsClient) GetETHBalance(
 }
 
 // MultiETHBalancesRequest contains the request parameters for GetMultiETHBalances.
+// As for ETHBalanceRequest, only the latest, earliest and pending tags are
+// accepted.
 type MultiETHBalancesRequest struct {
 	Addresses []common.Address `etherscan:"address"`
 	Tag       ecommon.BlockParameter
@@ -88,6 +92,10 @@ type TransactionInfo struct {
 	Gas             uint64
 	GasUsed         uint64 `etherscan:"gasUsed"`
 	IsError         bool   `etherscan:"isError,num"`
+
+	// Extra holds any response fields not decoded into other fields, such
+	// as fields added to the API after this struct.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
 }
 
 // NormalTxInfo contains information on normal transactions returned by ListNormalTransactions.
@@ -101,6 +109,8 @@ type NormalTxInfo struct {
 	TxReceiptStatus   string      `etherscan:"txreceipt_status"`
 	CumulativeGasUsed uint64      `etherscan:"cumulativeGasUsed"`
 	Confirmations     uint64
+	MethodID          []byte `etherscan:"methodId,omitempty"`
+	FunctionName      string `etherscan:"functionName,omitempty"`
 }
 
 // InternalTxInfo contains information on internal transactions.
@@ -209,6 +219,9 @@ type BaseTokenTransferInfo struct {
 	GasUsed           uint64   `etherscan:"gasUsed"`
 	CumulativeGasUsed uint64   `etherscan:"cumulativeGasUsed"`
 	Confirmations     uint64
+
+	// Extra holds any response fields not decoded into other fields.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
 }
 
 // TokenTransferInfo contains information on an ERC20 token transfer.
@@ -277,6 +290,9 @@ type BlockInfo struct {
 	BlockNumber uint64    `etherscan:"blockNumber"`
 	Timestamp   time.Time `etherscan:"timeStamp"`
 	BlockReward *big.Int  `etherscan:"blockReward"`
+
+	// Extra holds any response fields not decoded into the fields above.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
 }
 
 // ListBlocksMined lists blocks that were mined by a specific address.
@@ -303,13 +319,68 @@ type HistoricalETHRequest struct {
 // particular block number.
 func (c *AccountsClient) GetHistoricalETHBalance(
 	ctx context.Context, req *HistoricalETHRequest,
-) (result *big.Int, err error) {
-	err = c.API.Call(ctx, &httpapi.CallParams{
+) (*big.Int, error) {
+	result := new(marshallers.BigInt)
+	err := c.API.Call(ctx, &httpapi.CallParams{
 		Module:  ecommon.AccountsModule,
 		Action:  "balancehistory",
 		Request: req,
+		Result:  result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Unwrap(), nil
+}
+
+// BeaconWithdrawalsRequest contains the request parameters for ListBeaconWithdrawals.
+type BeaconWithdrawalsRequest struct {
+	Address    common.Address
+	StartBlock uint64
+	EndBlock   uint64
+	Sort       ecommon.SortingPreference
+	ecommon.Pagination
+}
+
+// BeaconWithdrawal contains information on a beacon chain validator
+// withdrawal credited to an address.
+type BeaconWithdrawal struct {
+	WithdrawalIndex uint64 `etherscan:"withdrawalIndex"`
+	ValidatorIndex  uint64 `etherscan:"validatorIndex"`
+	Address         common.Address
+	Amount          *big.Int
+	BlockNumber     uint64    `etherscan:"blockNumber"`
+	Timestamp       time.Time `etherscan:"timestamp"`
+
+	// Extra holds any response fields not decoded into the fields above.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
+}
+
+// gweiToWei is the number of wei in one gwei.
+var gweiToWei = big.NewInt(1e9)
+
+// ListBeaconWithdrawals lists the beacon chain withdrawals made to an
+// address. Amounts are returned in wei.
+func (c *AccountsClient) ListBeaconWithdrawals(
+	ctx context.Context, req *BeaconWithdrawalsRequest,
+) (result []BeaconWithdrawal, err error) {
+	err = c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.AccountsModule,
+		Action:  "txsBeaconWithdrawal",
+		Request: req,
 		Result:  &result,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return result, err
+	// Etherscan reports withdrawal amounts in gwei.
+	for i := range result {
+		if result[i].Amount != nil {
+			result[i].Amount.Mul(result[i].Amount, gweiToWei)
+		}
+	}
+
+	return result, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ryanc414/etherscan-api-go"
 	"github.com/ryanc414/etherscan-api-go/accounts"
 	ecommon "github.com/ryanc414/etherscan-api-go/common"
@@ -37,6 +38,15 @@ func TestAccount(t *testing.T) {
 		assert.Equal(t, "40891626854930000000000", bal.String())
 	})
 
+	t.Run("GetHistoricalETHBalance", func(t *testing.T) {
+		bal, err := client.Accounts.GetHistoricalETHBalance(ctx, &accounts.HistoricalETHRequest{
+			Address:     common.HexToAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"),
+			BlockNumber: 15000000,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "40771292413450000000000", bal.String())
+	})
+
 	var multiETHBalAddrs = []common.Address{
 		common.HexToAddress("0xddbd2b932c763ba5b1b7ae3b362eac3e8d40121a"),
 		common.HexToAddress("0x63a9975ba31b0b9626b34300f7f627147df1f526"),
@@ -67,6 +77,21 @@ func TestAccount(t *testing.T) {
 		cupaloy.SnapshotT(t, txs)
 	})
 
+	t.Run("ListNormalTxsMethod", func(t *testing.T) {
+		txs, err := client.Accounts.ListNormalTransactions(ctx, &accounts.ListTxRequest{
+			Address:    common.HexToAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"),
+			StartBlock: 17000000,
+			EndBlock:   17000100,
+			Sort:       ecommon.SortingPreferenceAsc,
+		})
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+
+		assert.Equal(t, hexutil.MustDecode("0xa9059cbb"), txs[0].MethodID)
+		assert.Equal(t, "transfer(address _to, uint256 _value)", txs[0].FunctionName)
+		assert.JSONEq(t, `"0"`, string(txs[0].Extra["l1Fee"]))
+	})
+
 	t.Run("ListInternalTxs", func(t *testing.T) {
 		txs, err := client.Accounts.ListInternalTransactions(ctx, &accounts.ListTxRequest{
 			Address:    common.HexToAddress("0x2c1ba59d6f58433fb1eaee7d20b26ed83bda51a3"),
@@ -77,6 +102,11 @@ func TestAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, txs, 2)
 
+		assert.JSONEq(t, `""`, string(txs[0].Extra["errCode"]))
+		for i := range txs {
+			txs[i].Extra = nil
+		}
+
 		cupaloy.SnapshotT(t, txs)
 	})
 
@@ -88,6 +118,11 @@ func TestAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, txs, 1)
 
+		assert.JSONEq(t, `""`, string(txs[0].Extra["errCode"]))
+		for i := range txs {
+			txs[i].Extra = nil
+		}
+
 		cupaloy.SnapshotT(t, txs)
 	})
 
@@ -100,6 +135,11 @@ func TestAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, txs, 2)
 
+		assert.JSONEq(t, `""`, string(txs[0].Extra["errCode"]))
+		for i := range txs {
+			txs[i].Extra = nil
+		}
+
 		cupaloy.SnapshotT(t, txs)
 	})
 
@@ -112,6 +152,11 @@ func TestAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, txs, 2)
 
+		assert.JSONEq(t, `"deprecated"`, string(txs[0].Extra["input"]))
+		for i := range txs {
+			txs[i].Extra = nil
+		}
+
 		cupaloy.SnapshotT(t, txs)
 	})
 
@@ -127,6 +172,11 @@ func TestAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, txs, 2)
 
+		assert.JSONEq(t, `"deprecated"`, string(txs[0].Extra["input"]))
+		for i := range txs {
+			txs[i].Extra = nil
+		}
+
 		cupaloy.SnapshotT(t, txs)
 	})
 
@@ -140,4 +190,19 @@ func TestAccount(t *testing.T) {
 
 		cupaloy.SnapshotT(t, blocks)
 	})
+
+	t.Run("ListBeaconWithdrawals", func(t *testing.T) {
+		withdrawals, err := client.Accounts.ListBeaconWithdrawals(ctx, &accounts.BeaconWithdrawalsRequest{
+			Address:    common.HexToAddress("0xB9D7934878B5FB9610B3fE8A5e441e8fad7E293f"),
+			StartBlock: 0,
+			EndBlock:   99999999,
+			Sort:       ecommon.SortingPreferenceAsc,
+			Pagination: ecommon.Pagination{Page: 1, Offset: 100},
+		})
+		require.NoError(t, err)
+		require.Len(t, withdrawals, 2)
+		assert.Equal(t, "3402931000000000", withdrawals[0].Amount.String())
+
+		cupaloy.SnapshotT(t, withdrawals)
+	})
 }
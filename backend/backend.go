@@ -0,0 +1,223 @@
+// Package backend adapts the Etherscan proxy, accounts and logs modules to
+// the go-ethereum client interfaces, so that abigen-generated contract
+// bindings and code written against ethclient.Client can be used with only
+// an Etherscan API key.
+package backend
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/logs"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+// Backend implements bind.ContractBackend and the go-ethereum chain reader
+// interfaces on top of the Etherscan proxy, accounts and logs modules.
+// Subscriptions are served by polling. The proxy module cannot look up
+// blocks by hash, so methods taking a block hash return
+// ErrBlockHashUnsupported.
+type Backend struct {
+	Proxy    *proxy.ProxyClient
+	Accounts *accounts.AccountsClient
+	Logs     *logs.LogsClient
+
+	// PollInterval is the interval between polls for new blocks in
+	// SubscribeFilterLogs and SubscribeNewHead. Defaults to 15 seconds.
+	PollInterval time.Duration
+}
+
+var (
+	_ bind.ContractBackend       = (*Backend)(nil)
+	_ ethereum.ChainReader       = (*Backend)(nil)
+	_ ethereum.ChainStateReader  = (*Backend)(nil)
+	_ ethereum.TransactionReader = (*Backend)(nil)
+	_ ethereum.BlockNumberReader = (*Backend)(nil)
+	_ ethereum.GasPricer         = (*Backend)(nil)
+	_ ethereum.LogFilterer       = (*Backend)(nil)
+)
+
+// ErrBlockHashUnsupported is returned when querying by block hash, which
+// the proxy module does not support.
+var ErrBlockHashUnsupported = errors.New("querying by block hash is not supported")
+
+// CodeAt implements bind.ContractCaller and ethereum.ChainStateReader.
+func (b *Backend) CodeAt(
+	ctx context.Context, contract common.Address, blockNumber *big.Int,
+) ([]byte, error) {
+	return b.Proxy.GetCode(ctx, &proxy.GetCodeRequest{
+		Address: contract,
+		Tag:     blockTag(blockNumber),
+	})
+}
+
+// CallContract implements bind.ContractCaller.
+func (b *Backend) CallContract(
+	ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int,
+) ([]byte, error) {
+	req := proxy.CallRequest{
+		From:                 optionalFrom(call.From),
+		Gas:                  optionalGas(call.Gas),
+		GasPrice:             call.GasPrice,
+		MaxFeePerGas:         call.GasFeeCap,
+		MaxPriorityFeePerGas: call.GasTipCap,
+		Value:                call.Value,
+		Data:                 call.Data,
+		Tag:                  blockTag(blockNumber),
+	}
+
+	if call.To != nil {
+		req.To = *call.To
+	}
+
+	return b.Proxy.Call(ctx, &req)
+}
+
+// PendingCodeAt implements bind.ContractTransactor.
+func (b *Backend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.Proxy.GetCode(ctx, &proxy.GetCodeRequest{
+		Address: account,
+		Tag:     ecommon.BlockTagNamed(ecommon.BlockParameterPending),
+	})
+}
+
+// PendingNonceAt implements bind.ContractTransactor.
+func (b *Backend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.Proxy.GetTransactionCount(ctx, &proxy.TxCountRequest{
+		Address: account,
+		Tag:     ecommon.BlockTagNamed(ecommon.BlockParameterPending),
+	})
+}
+
+// SuggestGasPrice implements bind.ContractTransactor and ethereum.GasPricer.
+func (b *Backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.Proxy.GasPrice(ctx)
+}
+
+// SuggestGasTipCap implements bind.ContractTransactor. The tip is derived
+// from the suggested gas price less the base fee of the latest block, as the
+// proxy module has no eth_maxPriorityFeePerGas method.
+func (b *Backend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	gasPrice, err := b.Proxy.GasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := b.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.BaseFee == nil {
+		return gasPrice, nil
+	}
+
+	tip := new(big.Int).Sub(gasPrice, header.BaseFee)
+	if tip.Sign() < 0 {
+		tip.SetInt64(0)
+	}
+
+	return tip, nil
+}
+
+// EstimateGas implements bind.ContractTransactor.
+func (b *Backend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	req := proxy.EstimateGasRequest{
+		From:                 optionalFrom(call.From),
+		Gas:                  optionalGas(call.Gas),
+		GasPrice:             call.GasPrice,
+		MaxFeePerGas:         call.GasFeeCap,
+		MaxPriorityFeePerGas: call.GasTipCap,
+		Value:                call.Value,
+		Data:                 call.Data,
+	}
+
+	if call.To != nil {
+		req.To = *call.To
+	}
+
+	gas, err := b.Proxy.EstimateGas(ctx, &req)
+	if err != nil {
+		return 0, err
+	}
+
+	if !gas.IsUint64() {
+		return 0, errors.Errorf("gas estimate %s overflows uint64", gas)
+	}
+
+	return gas.Uint64(), nil
+}
+
+// SendTransaction implements bind.ContractTransactor.
+func (b *Backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	hash, err := b.Proxy.SendRawTransaction(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	if hash != tx.Hash() {
+		return errors.Errorf("sent transaction %s but got hash %s", tx.Hash(), hash)
+	}
+
+	return nil
+}
+
+// blockTag converts a go-ethereum block number argument to a BlockTag. A nil
+// number denotes the latest block, and negative numbers denote the named
+// blocks defined by the rpc package.
+func blockTag(number *big.Int) ecommon.BlockTag {
+	if number == nil {
+		return ecommon.BlockTagNamed(ecommon.BlockParameterLatest)
+	}
+
+	if number.Sign() >= 0 {
+		return ecommon.BlockTagNumber(number.Uint64())
+	}
+
+	switch rpc.BlockNumber(number.Int64()) {
+	case rpc.PendingBlockNumber:
+		return ecommon.BlockTagNamed(ecommon.BlockParameterPending)
+
+	case rpc.FinalizedBlockNumber:
+		return ecommon.BlockTagNamed(ecommon.BlockParameterFinalized)
+
+	case rpc.SafeBlockNumber:
+		return ecommon.BlockTagNamed(ecommon.BlockParameterSafe)
+
+	case rpc.EarliestBlockNumber:
+		return ecommon.BlockTagNamed(ecommon.BlockParameterEarliest)
+
+	default:
+		return ecommon.BlockTagNamed(ecommon.BlockParameterLatest)
+	}
+}
+
+func optionalFrom(from common.Address) *common.Address {
+	if from == (common.Address{}) {
+		return nil
+	}
+
+	return &from
+}
+
+func optionalGas(gas uint64) *big.Int {
+	if gas == 0 {
+		return nil
+	}
+
+	return new(big.Int).SetUint64(gas)
+}
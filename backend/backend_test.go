@@ -0,0 +1,163 @@
+package backend_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/backend"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const erc20ABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view",
+	 "inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable",
+	 "inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],
+	 "outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+	 {"indexed":true,"name":"from","type":"address"},
+	 {"indexed":true,"name":"to","type":"address"},
+	 {"indexed":false,"name":"value","type":"uint256"}]}
+]`
+
+var (
+	token  = common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	holder = common.HexToAddress("0xe16359506c028e51f16be38986ec5746251e9724")
+)
+
+func newBackend(t *testing.T) *backend.Backend {
+	m := testbed.NewMockServer("backend", false)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+	})
+
+	return &backend.Backend{
+		Proxy:        &client.Proxy,
+		Accounts:     &client.Accounts,
+		Logs:         &client.Logs,
+		PollInterval: time.Millisecond,
+	}
+}
+
+func TestBackend(t *testing.T) {
+	b := newBackend(t)
+
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	require.NoError(t, err)
+
+	contract := bind.NewBoundContract(token, parsed, b, b, b)
+	ctx := context.Background()
+
+	t.Run("Call", func(t *testing.T) {
+		var result []interface{}
+		err := contract.Call(&bind.CallOpts{Context: ctx}, &result, "balanceOf", holder)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "27054070000000000", result[0].(*big.Int).String())
+	})
+
+	t.Run("Transact", func(t *testing.T) {
+		key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		require.NoError(t, err)
+
+		opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1))
+		require.NoError(t, err)
+		opts.Context = ctx
+
+		tx, err := contract.Transact(opts, "transfer", holder, big.NewInt(1e18))
+		require.NoError(t, err)
+
+		assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+		assert.Equal(t, uint64(42), tx.Nonce())
+		assert.Equal(t, uint64(46097), tx.Gas())
+		assert.Equal(t, big.NewInt(5000000000), tx.GasTipCap())
+		assert.Equal(t, big.NewInt(55000000000), tx.GasFeeCap())
+	})
+
+	transferTopic := parsed.Events["Transfer"].ID
+	fromFilter := []interface{}{holder}
+
+	t.Run("FilterLogs", func(t *testing.T) {
+		logs, sub, err := contract.FilterLogs(
+			&bind.FilterOpts{Start: 19500000, End: newUint64(19500010), Context: ctx},
+			"Transfer", fromFilter,
+		)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		// The subscription ends once all logs are delivered, but the channel
+		// is never closed.
+		require.NoError(t, <-sub.Err())
+
+		var received []types.Log
+		for len(logs) > 0 {
+			received = append(received, <-logs)
+		}
+
+		require.Len(t, received, 1)
+		assert.Equal(t, transferTopic, received[0].Topics[0])
+		assert.Equal(t, common.BytesToHash(holder.Bytes()), received[0].Topics[1])
+	})
+
+	t.Run("FilterLogsTopicOptions", func(t *testing.T) {
+		approvalTopic := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+		// A nil FromBlock starts from the genesis block, and each option of
+		// the first topic is queried separately.
+		logs, err := b.FilterLogs(ctx, ethereum.FilterQuery{
+			ToBlock:   big.NewInt(19500010),
+			Addresses: []common.Address{token},
+			Topics:    [][]common.Hash{{transferTopic, approvalTopic}},
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+
+		for _, log := range logs {
+			assert.Equal(t, transferTopic, log.Topics[0])
+		}
+	})
+
+	t.Run("FilterLogsBlockHash", func(t *testing.T) {
+		hash := common.HexToHash("0x1")
+		_, err := b.FilterLogs(ctx, ethereum.FilterQuery{BlockHash: &hash, Addresses: []common.Address{token}})
+		assert.ErrorIs(t, err, backend.ErrBlockHashUnsupported)
+	})
+
+	t.Run("SubscribeFilterLogs", func(t *testing.T) {
+		start := uint64(19500000)
+		logs, sub, err := contract.WatchLogs(&bind.WatchOpts{Start: &start, Context: ctx}, "Transfer", fromFilter)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		select {
+		case log := <-logs:
+			assert.Equal(t, uint64(19500000), log.BlockNumber)
+		case err := <-sub.Err():
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for log")
+		}
+	})
+}
+
+func newUint64(v uint64) *uint64 {
+	return &v
+}
@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockNumber implements ethereum.BlockNumberReader.
+func (b *Backend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.Proxy.BlockNumber(ctx)
+}
+
+// BlockByHash implements ethereum.ChainReader. It always returns
+// ErrBlockHashUnsupported.
+func (b *Backend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return nil, ErrBlockHashUnsupported
+}
+
+// BlockByNumber implements ethereum.ChainReader. A nil number returns the
+// latest block, and the named blocks of the rpc package are fetched by tag.
+// The block is verified against its hash and roots, and has no uncle
+// headers. proxy.ErrUnsupportedTxType is returned for blocks with set-code
+// transactions, whose headers are still available from HeaderByNumber.
+func (b *Backend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, err := b.Proxy.GetBlockByTagFull(ctx, blockTag(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Hash == (common.Hash{}) {
+		return nil, ethereum.NotFound
+	}
+
+	return block.Block()
+}
+
+// HeaderByHash implements ethereum.ChainReader. It always returns
+// ErrBlockHashUnsupported.
+func (b *Backend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return nil, ErrBlockHashUnsupported
+}
+
+// HeaderByNumber implements bind.ContractTransactor and ethereum.ChainReader.
+// A nil number returns the latest header, and the named blocks of the rpc
+// package are fetched by tag.
+func (b *Backend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	block, err := b.Proxy.GetBlockByTagSummary(ctx, blockTag(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Hash == (common.Hash{}) {
+		return nil, ethereum.NotFound
+	}
+
+	return block.Header()
+}
+
+// TransactionCount implements ethereum.ChainReader. It always returns
+// ErrBlockHashUnsupported.
+func (b *Backend) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return 0, ErrBlockHashUnsupported
+}
+
+// TransactionInBlock implements ethereum.ChainReader. It always returns
+// ErrBlockHashUnsupported.
+func (b *Backend) TransactionInBlock(
+	ctx context.Context, blockHash common.Hash, index uint,
+) (*types.Transaction, error) {
+	return nil, ErrBlockHashUnsupported
+}
+
+// SubscribeNewHead implements ethereum.ChainReader by polling for new blocks
+// every PollInterval. The subscription ends with an error if a poll fails.
+// Chain reorganisations are not detected.
+func (b *Backend) SubscribeNewHead(
+	ctx context.Context, ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	head, err := b.Proxy.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.pollBlocks(head+1, func(ctx context.Context, quit <-chan struct{}, from, to uint64) error {
+		for number := from; number <= to; number++ {
+			header, err := b.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return err
+			}
+
+			select {
+			case ch <- header:
+			case <-quit:
+				return nil
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+// TransactionByHash implements ethereum.TransactionReader. The transaction is
+// verified against its hash.
+func (b *Backend) TransactionByHash(
+	ctx context.Context, txHash common.Hash,
+) (tx *types.Transaction, isPending bool, err error) {
+	info, err := b.Proxy.GetTransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if info.Hash == (common.Hash{}) {
+		return nil, false, ethereum.NotFound
+	}
+
+	tx, err = info.Transaction()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tx, info.BlockHash == (common.Hash{}), nil
+}
+
+// TransactionReceipt implements ethereum.TransactionReader. The receipt's
+// logs bloom is verified against its logs.
+func (b *Backend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	info, err := b.Proxy.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.TransactionHash == (common.Hash{}) {
+		return nil, ethereum.NotFound
+	}
+
+	return info.Receipt()
+}
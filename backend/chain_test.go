@@ -0,0 +1,131 @@
+package backend_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanc414/etherscan-api-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainReader(t *testing.T) {
+	b := newBackend(t)
+	ctx := context.Background()
+
+	const headNumber = 19500000
+	blockHash := common.HexToHash("0x6557ff2c1746ee6df9af644ac874e23a4ce5a39e4885d9a11b3107da8bd911f6")
+	txHash := common.HexToHash("0x34298606ddeb907124f9e839f57632f2f1ec27db6df8e1a6b20d9ac7c03f55f2")
+	missingHash := common.HexToHash("0xabababababababababababababababababababababababababababababababab")
+
+	t.Run("BlockNumber", func(t *testing.T) {
+		number, err := b.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(headNumber), number)
+	})
+
+	t.Run("BlockByNumber", func(t *testing.T) {
+		block, err := b.BlockByNumber(ctx, big.NewInt(headNumber))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(headNumber), block.NumberU64())
+		assert.Equal(t, blockHash, block.Hash())
+		assert.Len(t, block.Transactions(), 4)
+
+		latest, err := b.BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, block.Hash(), latest.Hash())
+	})
+
+	t.Run("HeaderByNumber", func(t *testing.T) {
+		header, err := b.HeaderByNumber(ctx, big.NewInt(rpc.LatestBlockNumber.Int64()))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(headNumber), header.Number)
+	})
+
+	t.Run("HeaderByNumberNotFound", func(t *testing.T) {
+		_, err := b.HeaderByNumber(ctx, big.NewInt(headNumber+1))
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+
+	t.Run("HeaderByNumberFinalized", func(t *testing.T) {
+		header, err := b.HeaderByNumber(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		require.NoError(t, err)
+		assert.Equal(t, blockHash, header.Hash())
+	})
+
+	t.Run("BlockByNumberFinalized", func(t *testing.T) {
+		block, err := b.BlockByNumber(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		require.NoError(t, err)
+		assert.Equal(t, blockHash, block.Hash())
+	})
+
+	t.Run("ByBlockHash", func(t *testing.T) {
+		_, err := b.HeaderByHash(ctx, blockHash)
+		assert.ErrorIs(t, err, backend.ErrBlockHashUnsupported)
+
+		_, err = b.TransactionInBlock(ctx, blockHash, 0)
+		assert.ErrorIs(t, err, backend.ErrBlockHashUnsupported)
+	})
+
+	t.Run("TransactionByHash", func(t *testing.T) {
+		tx, isPending, err := b.TransactionByHash(ctx, txHash)
+		require.NoError(t, err)
+		assert.False(t, isPending)
+		assert.Equal(t, txHash, tx.Hash())
+		assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	})
+
+	t.Run("TransactionByHashNotFound", func(t *testing.T) {
+		_, _, err := b.TransactionByHash(ctx, missingHash)
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+
+	t.Run("TransactionReceipt", func(t *testing.T) {
+		receipt, err := b.TransactionReceipt(ctx, txHash)
+		require.NoError(t, err)
+		assert.Equal(t, txHash, receipt.TxHash)
+		assert.Equal(t, big.NewInt(headNumber), receipt.BlockNumber)
+	})
+
+	t.Run("TransactionReceiptNotFound", func(t *testing.T) {
+		_, err := b.TransactionReceipt(ctx, missingHash)
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+}
+
+func TestChainStateReader(t *testing.T) {
+	b := newBackend(t)
+	ctx := context.Background()
+	atBlock := big.NewInt(19500000)
+
+	t.Run("BalanceAt", func(t *testing.T) {
+		balance, err := b.BalanceAt(ctx, holder, atBlock)
+		require.NoError(t, err)
+		assert.Equal(t, "1250000000000000000", balance.String())
+
+		balance, err = b.BalanceAt(ctx, holder, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "1300000000000000000", balance.String())
+
+		balance, err = b.BalanceAt(ctx, holder, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		require.NoError(t, err)
+		assert.Equal(t, "1250000000000000000", balance.String())
+	})
+
+	t.Run("StorageAt", func(t *testing.T) {
+		value, err := b.StorageAt(ctx, token, common.Hash{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Wrapped Ether", string(value[:13]))
+	})
+
+	t.Run("NonceAt", func(t *testing.T) {
+		nonce, err := b.NonceAt(ctx, holder, atBlock)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), nonce)
+	})
+}
@@ -0,0 +1,183 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/logs"
+)
+
+// FilterLogs implements bind.ContractFilterer and ethereum.LogFilterer. The logs module filters on a
+// single address and exact topics, so each address is queried separately
+// for each of the first topic's options, and any remaining topic criteria
+// are applied to the results. Results are paged through, so are not
+// limited to logs.MaxLogsPerPage. As with ethclient, a nil FromBlock is the
+// genesis block and a nil ToBlock the latest block.
+func (b *Backend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if q.BlockHash != nil {
+		return nil, ErrBlockHashUnsupported
+	}
+
+	if len(q.Addresses) == 0 {
+		return nil, errors.New("at least one address is required to filter logs")
+	}
+
+	// A nil first topic leaves the query unfiltered by topic.
+	firstTopics := []*common.Hash{nil}
+	if len(q.Topics) > 0 && len(q.Topics[0]) > 0 {
+		firstTopics = make([]*common.Hash, len(q.Topics[0]))
+		for i := range q.Topics[0] {
+			firstTopics[i] = &q.Topics[0][i]
+		}
+	}
+
+	var result []types.Log
+
+	for _, addr := range q.Addresses {
+		for _, topic := range firstTopics {
+			req := logs.LogsRequest{
+				FromBlock: fromBlockParam(q.FromBlock),
+				ToBlock:   toBlockParam(q.ToBlock),
+				Address:   addr,
+			}
+
+			if topic != nil {
+				req.Topics = []common.Hash{*topic}
+			}
+
+			rsp, err := b.Logs.GetAllLogs(ctx, &req)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := range rsp {
+				log := convertLog(&rsp[i])
+				if matchTopics(log.Topics, q.Topics) {
+					result = append(result, log)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+
+		return result[i].Index < result[j].Index
+	})
+
+	return result, nil
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer and
+// ethereum.LogFilterer by polling for logs in new blocks every PollInterval.
+// The subscription ends with an error if a poll fails. Chain reorganisations
+// are not detected.
+func (b *Backend) SubscribeFilterLogs(
+	ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	if q.BlockHash != nil {
+		return nil, ErrBlockHashUnsupported
+	}
+
+	next, err := b.Proxy.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.FromBlock != nil && q.FromBlock.Sign() >= 0 {
+		next = q.FromBlock.Uint64()
+	} else {
+		next++
+	}
+
+	return b.pollBlocks(next, func(ctx context.Context, quit <-chan struct{}, from, to uint64) error {
+		pollQuery := q
+		pollQuery.FromBlock = new(big.Int).SetUint64(from)
+		pollQuery.ToBlock = new(big.Int).SetUint64(to)
+
+		found, err := b.FilterLogs(ctx, pollQuery)
+		if err != nil {
+			return err
+		}
+
+		for i := range found {
+			select {
+			case ch <- found[i]:
+			case <-quit:
+				return nil
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+// fromBlockParam converts the start of a filter query's block range. A nil
+// number is the genesis block. Named blocks, which are negative, are
+// treated as the latest block.
+func fromBlockParam(number *big.Int) logs.LogsBlockParam {
+	if number == nil {
+		return logs.LogsBlockParam{Number: 0}
+	}
+
+	return toBlockParam(number)
+}
+
+// toBlockParam converts the end of a filter query's block range. A nil
+// number and named blocks are treated as the latest block.
+func toBlockParam(number *big.Int) logs.LogsBlockParam {
+	if number == nil || number.Sign() < 0 {
+		return logs.LogsBlockParam{Latest: true}
+	}
+
+	return logs.LogsBlockParam{Number: number.Uint64()}
+}
+
+func convertLog(rsp *logs.LogResponse) types.Log {
+	return types.Log{
+		Address:     rsp.Address,
+		Topics:      rsp.Topics,
+		Data:        rsp.Data,
+		BlockNumber: rsp.BlockNumber,
+		TxHash:      rsp.TransactionHash,
+		TxIndex:     uint(rsp.TransactionIndex),
+		BlockHash:   rsp.BlockHash,
+		Index:       uint(rsp.LogIndex),
+	}
+}
+
+// matchTopics returns whether log topics match the topic criteria of a
+// filter query, where each position matches any of its listed topics and an
+// empty position matches anything.
+func matchTopics(topics []common.Hash, criteria [][]common.Hash) bool {
+	if len(criteria) > len(topics) {
+		return false
+	}
+
+	for i, options := range criteria {
+		if len(options) == 0 {
+			continue
+		}
+
+		matched := false
+		for _, topic := range options {
+			if topics[i] == topic {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const defaultPollInterval = 15 * time.Second
+
+// blockRangeHandler handles the new blocks from and to inclusive that were
+// found by a poll. It should return early if quit is closed.
+type blockRangeHandler func(ctx context.Context, quit <-chan struct{}, from, to uint64) error
+
+// pollBlocks returns a subscription that polls for new blocks every
+// PollInterval, starting at block next, and passes each new range of blocks
+// to handle. The subscription ends with the first error from a poll.
+func (b *Backend) pollBlocks(next uint64, handle blockRangeHandler) ethereum.Subscription {
+	interval := b.PollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			select {
+			case <-quit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		for {
+			select {
+			case <-quit:
+				return nil
+
+			case <-ticker.C:
+			}
+
+			head, err := b.Proxy.BlockNumber(ctx)
+			if err != nil {
+				return pollError(quit, err)
+			}
+
+			if head < next {
+				continue
+			}
+
+			if err := handle(ctx, quit, next, head); err != nil {
+				return pollError(quit, err)
+			}
+
+			next = head + 1
+		}
+	})
+}
+
+// pollError returns err unless the subscription has been unsubscribed, in
+// which case err is the result of cancelling the poll.
+func pollError(quit <-chan struct{}, err error) error {
+	select {
+	case <-quit:
+		return nil
+	default:
+		return err
+	}
+}
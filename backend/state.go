@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+// BalanceAt implements ethereum.ChainStateReader. Etherscan's balance action
+// only accepts the latest, earliest and pending blocks, so balances at other
+// blocks are read from the balancehistory action, which requires an API Pro
+// key. The safe and finalized blocks are first resolved to their numbers.
+func (b *Backend) BalanceAt(
+	ctx context.Context, account common.Address, blockNumber *big.Int,
+) (*big.Int, error) {
+	tag := blockTag(blockNumber)
+
+	number, ok := tag.Number()
+	if !ok {
+		switch param, _ := tag.Param(); param {
+		case ecommon.BlockParameterLatest, ecommon.BlockParameterEarliest, ecommon.BlockParameterPending:
+			return b.Accounts.GetETHBalance(ctx, &accounts.ETHBalanceRequest{
+				Address: account,
+				Tag:     param,
+			})
+		}
+
+		block, err := b.Proxy.GetBlockByTagSummary(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		number = block.Number
+	}
+
+	return b.Accounts.GetHistoricalETHBalance(ctx, &accounts.HistoricalETHRequest{
+		Address:     account,
+		BlockNumber: number,
+	})
+}
+
+// StorageAt implements ethereum.ChainStateReader.
+func (b *Backend) StorageAt(
+	ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int,
+) ([]byte, error) {
+	return b.Proxy.GetStorageAt(ctx, &proxy.GetStorageRequest{
+		Address:  account,
+		Position: key,
+		Tag:      blockTag(blockNumber),
+	})
+}
+
+// NonceAt implements ethereum.ChainStateReader.
+func (b *Backend) NonceAt(
+	ctx context.Context, account common.Address, blockNumber *big.Int,
+) (uint64, error) {
+	return b.Proxy.GetTransactionCount(ctx, &proxy.TxCountRequest{
+		Address: account,
+		Tag:     blockTag(blockNumber),
+	})
+}
@@ -0,0 +1,299 @@
+// Package bytecode inspects contract runtime bytecode, as returned by the
+// proxy module's GetCode, without needing verified source code. It splits off
+// the compiler metadata, extracts the function selectors of the dispatcher
+// and classifies contracts against common standards.
+package bytecode
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ryanc414/etherscan-api-go/storage"
+)
+
+// Selectors returns the function selectors in the dispatcher of runtime
+// bytecode, in the order they appear. Metadata is split off before the code
+// is scanned.
+//
+// The dispatcher follows the prologue that loads the selector of the call,
+// and compares it against each selector in turn, jumping to the function's
+// entry on a match. Larger dispatchers are split by binary search pivots,
+// whose jumps are followed. Pushes outside the dispatcher, such as constants
+// compared in function bodies, are ignored.
+func Selectors(code []byte) [][4]byte {
+	code, _ = SplitMetadata(code)
+	ops := disassemble(code)
+
+	start, ok := dispatcherStart(ops)
+	if !ok {
+		return nil
+	}
+
+	jumpdests := make(map[uint64]int)
+	for i, op := range ops {
+		if op.code == opJUMPDEST {
+			jumpdests[uint64(op.pc)] = i
+		}
+	}
+
+	var selectors [][4]byte
+	seen := make(map[[4]byte]bool)
+
+	blocks := []int{start}
+	visited := make(map[int]bool)
+
+	for len(blocks) > 0 {
+		i := blocks[0]
+		blocks = blocks[1:]
+
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+
+		if i < len(ops) && ops[i].code == opJUMPDEST {
+			i++
+		}
+
+		for i+dispatchLen <= len(ops) {
+			step := ops[i : i+dispatchLen]
+
+			dest, ok := jumpdests[jumpTarget(step[3:])]
+			if !ok {
+				break
+			}
+
+			if selector, ok := selectorCompare(step); ok {
+				if !seen[selector] {
+					seen[selector] = true
+					selectors = append(selectors, selector)
+				}
+			} else if isPivot(step) {
+				blocks = append(blocks, dest)
+			} else {
+				break
+			}
+
+			i += dispatchLen
+		}
+	}
+
+	return selectors
+}
+
+// dispatchLen is the number of instructions in each step of the dispatcher:
+// a comparison of the selector of the call followed by "PUSHn dest JUMPI".
+const dispatchLen = 5
+
+// dispatcherStart returns the index of the first instruction of the
+// dispatcher, following the prologue that loads the selector of the call
+// with "PUSH 0 CALLDATALOAD" and shifts it down with SHR or, in code
+// compiled for EVM versions without SHR, DIV. The shift may be followed by a
+// mask of the low 4 bytes, "PUSH4 0xffffffff AND" or "AND" with the mask
+// pushed first.
+func dispatcherStart(ops []instruction) (int, bool) {
+	for i := 1; i < len(ops); i++ {
+		if ops[i].code != opCALLDATALOAD || !isPushOf(ops[i-1], common.Big0) {
+			continue
+		}
+
+		for shift := i + 1; shift < len(ops) && shift <= i+maxShiftLen; shift++ {
+			if ops[shift].code != opSHR && ops[shift].code != opDIV {
+				continue
+			}
+
+			start := shift + 1
+			switch {
+			case start+1 < len(ops) && isPushOf(ops[start], selectorMask) && ops[start+1].code == opAND:
+				start += 2
+			case start < len(ops) && ops[start].code == opAND:
+				start++
+			}
+
+			return start, true
+		}
+
+		return 0, false
+	}
+
+	return 0, false
+}
+
+// maxShiftLen is the number of instructions within which the selector of the
+// call is shifted after being loaded, as in "PUSH29 2**224 SWAP1 DIV".
+const maxShiftLen = 3
+
+// selectorMask masks the low 4 bytes of a word.
+var selectorMask = big.NewInt(0xffffffff)
+
+// isPushOf returns whether op pushes value.
+func isPushOf(op instruction, value *big.Int) bool {
+	return op.code.isPush() && new(big.Int).SetBytes(op.data).Cmp(value) == 0
+}
+
+// selectorCompare returns the selector compared for equality with the
+// selector of the call by a step of the dispatcher: either
+// "DUP1 PUSHn selector EQ" or, as solc emits for the first comparison,
+// "PUSHn selector DUP2 EQ". Only PUSH1 to PUSH4 are accepted, as compilers
+// push selectors with leading zero bytes using a shorter push, e.g. PUSH3
+// for 0x00fdd58e.
+func selectorCompare(step []instruction) ([4]byte, bool) {
+	var push instruction
+
+	switch {
+	case step[0].code == opDUP1 && step[2].code == opEQ:
+		push = step[1]
+
+	case step[1].code == opDUP2 && step[2].code == opEQ:
+		push = step[0]
+
+	default:
+		return [4]byte{}, false
+	}
+
+	if push.code < opPUSH1 || push.code > opPUSH4 {
+		return [4]byte{}, false
+	}
+
+	var selector [4]byte
+	copy(selector[4-len(push.data):], push.data)
+
+	return selector, true
+}
+
+// isPivot returns whether a step of the dispatcher is a binary search pivot,
+// "DUP1 PUSHn pivot GT" or LT, which jumps to the comparisons of the
+// selectors on one side of the pivot.
+func isPivot(step []instruction) bool {
+	return step[0].code == opDUP1 &&
+		step[1].code >= opPUSH1 && step[1].code <= opPUSH4 &&
+		(step[2].code == opGT || step[2].code == opLT)
+}
+
+// jumpTarget returns the destination of "PUSHn dest JUMPI", or an invalid
+// destination if the instructions are not a conditional jump.
+func jumpTarget(jump []instruction) uint64 {
+	if !jump[0].code.isPush() || len(jump[0].data) > 8 || jump[1].code != opJUMPI {
+		return math.MaxUint64
+	}
+
+	return new(big.Int).SetBytes(jump[0].data).Uint64()
+}
+
+// opcode is an EVM opcode. Only the opcodes needed to find selectors and
+// proxies are defined.
+type opcode byte
+
+const (
+	opDIV          opcode = 0x04
+	opLT           opcode = 0x10
+	opGT           opcode = 0x11
+	opEQ           opcode = 0x14
+	opAND          opcode = 0x16
+	opSHR          opcode = 0x1c
+	opCALLDATALOAD opcode = 0x35
+	opJUMPI        opcode = 0x57
+	opJUMPDEST     opcode = 0x5b
+	opPUSH0        opcode = 0x5f
+	opPUSH1        opcode = 0x60
+	opPUSH4        opcode = 0x63
+	opPUSH32       opcode = 0x7f
+	opDUP1         opcode = 0x80
+	opDUP2         opcode = 0x81
+	opDELEGATECALL opcode = 0xf4
+)
+
+func (op opcode) isPush() bool {
+	return op >= opPUSH0 && op <= opPUSH32
+}
+
+type instruction struct {
+	pc   int
+	code opcode
+	data []byte
+}
+
+// disassemble splits code into instructions. Push data truncated by the end
+// of the code is zero-padded, as when executed.
+func disassemble(code []byte) []instruction {
+	var ops []instruction
+
+	for pc := 0; pc < len(code); pc++ {
+		op := instruction{pc: pc, code: opcode(code[pc])}
+
+		if op.code.isPush() {
+			size := int(op.code - opPUSH0)
+			op.data = make([]byte, size)
+			copy(op.data, code[min(pc+1, len(code)):min(pc+1+size, len(code))])
+			pc += size
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops
+}
+
+var (
+	// minimalProxyPrefix and minimalProxySuffix surround the implementation
+	// address in an EIP-1167 minimal proxy.
+	minimalProxyPrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	minimalProxySuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+
+	// proxySlots are the storage slots in which proxies keep their
+	// implementation or beacon.
+	proxySlots = []common.Hash{
+		storage.EIP1967ImplementationSlot,
+		storage.EIP1967BeaconSlot,
+		storage.EIP1822ProxiableSlot,
+		storage.OZLegacyImplementationSlot,
+	}
+)
+
+// MinimalProxyImplementation returns the implementation of an EIP-1167
+// minimal proxy, or false if the code is not a minimal proxy.
+func MinimalProxyImplementation(code []byte) (common.Address, bool) {
+	if len(code) != len(minimalProxyPrefix)+common.AddressLength+len(minimalProxySuffix) ||
+		!bytes.HasPrefix(code, minimalProxyPrefix) ||
+		!bytes.HasSuffix(code, minimalProxySuffix) {
+		return common.Address{}, false
+	}
+
+	return common.BytesToAddress(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+common.AddressLength]), true
+}
+
+// IsProxy returns whether code is a proxy: either an EIP-1167 minimal proxy,
+// or code that delegates calls and references one of the well-known proxy
+// storage slots.
+func IsProxy(code []byte) bool {
+	if _, ok := MinimalProxyImplementation(code); ok {
+		return true
+	}
+
+	code, _ = SplitMetadata(code)
+
+	var delegates, slot bool
+	for _, op := range disassemble(code) {
+		switch {
+		case op.code == opDELEGATECALL:
+			delegates = true
+
+		case op.code == opPUSH32 && isProxySlot(op.data):
+			slot = true
+		}
+	}
+
+	return delegates && slot
+}
+
+func isProxySlot(data []byte) bool {
+	for _, slot := range proxySlots {
+		if bytes.Equal(data, slot.Bytes()) {
+			return true
+		}
+	}
+
+	return false
+}
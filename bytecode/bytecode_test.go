@@ -0,0 +1,305 @@
+package bytecode_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ryanc414/etherscan-api-go/bytecode"
+	"github.com/ryanc414/etherscan-api-go/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// preamble loads the selector of the call, as in solc output.
+	preamble = common.FromHex("0x608060405234801561001057600080fd5b50600436106100415760003560e01c")
+
+	erc20 = []string{
+		"totalSupply()",
+		"balanceOf(address)",
+		"transfer(address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"allowance(address,address)",
+	}
+
+	erc4626 = []string{
+		"asset()",
+		"totalAssets()",
+		"convertToShares(uint256)",
+		"convertToAssets(uint256)",
+		"deposit(uint256,address)",
+		"mint(uint256,address)",
+		"withdraw(uint256,address,address)",
+		"redeem(uint256,address,address)",
+	}
+)
+
+func selector(signature string) [4]byte {
+	return [4]byte(crypto.Keccak256([]byte(signature))[:4])
+}
+
+// dispatcher assembles a function dispatcher that compares the selector of
+// the call against each function, preceded by a binary search pivot. Every
+// jump leads to the same function entry, which reverts.
+func dispatcher(functions ...string) []byte {
+	// Leading zero bytes of selectors are omitted from their pushes, as by
+	// solc.
+	pushes := make([][]byte, len(functions))
+	entry := len(preamble) + 11
+	for i, function := range functions {
+		sel := selector(function)
+		pushes[i] = bytes.TrimLeft(sel[:], "\x00")
+		entry += len(pushes[i]) + 7
+	}
+
+	dest := binary.BigEndian.AppendUint16(nil, uint16(entry))
+	code := bytes.NewBuffer(append([]byte(nil), preamble...))
+
+	// DUP1 PUSH4 pivot GT PUSH2 dest JUMPI
+	code.Write(common.FromHex("0x80637ecebe001161"))
+	code.Write(dest)
+	code.WriteByte(0x57)
+
+	for _, push := range pushes {
+		// DUP1 PUSHn selector EQ PUSH2 dest JUMPI
+		code.Write([]byte{0x80, 0x5f + byte(len(push))})
+		code.Write(push)
+		code.Write([]byte{0x14, 0x61})
+		code.Write(dest)
+		code.WriteByte(0x57)
+	}
+
+	// JUMPDEST PUSH1 0 DUP1 REVERT
+	code.Write(common.FromHex("0x5b600080fd"))
+
+	return code.Bytes()
+}
+
+// withMetadata appends a CBOR metadata trailer to code.
+func withMetadata(code, meta []byte) []byte {
+	out := append(append([]byte(nil), code...), meta...)
+	return binary.BigEndian.AppendUint16(out, uint16(len(meta)))
+}
+
+func TestSplitMetadata(t *testing.T) {
+	ipfs := append([]byte{0x12, 0x20}, bytes.Repeat([]byte{0xab}, 32)...)
+	swarm := bytes.Repeat([]byte{0xcd}, 32)
+	code := dispatcher("totalSupply()")
+
+	t.Run("IPFS", func(t *testing.T) {
+		var meta bytes.Buffer
+		meta.Write(common.FromHex("0xa264697066735822"))
+		meta.Write(ipfs)
+		meta.Write(common.FromHex("0x64736f6c6343000813"))
+
+		runtime, metadata := bytecode.SplitMetadata(withMetadata(code, meta.Bytes()))
+		assert.Equal(t, code, runtime)
+		require.NotNil(t, metadata)
+
+		assert.Equal(t, "0.8.19", metadata.CompilerVersion)
+		assert.Equal(t, ipfs, metadata.IPFS)
+		assert.Equal(t, "QmZtnFaddFtzGNT8BxdHVbQrhSFdq1pWxud5z4fA4kxfDt", metadata.IPFSHash())
+		assert.Nil(t, metadata.Swarm)
+		assert.False(t, metadata.Experimental)
+		assert.Len(t, metadata.Raw, meta.Len()+2)
+	})
+
+	t.Run("Swarm", func(t *testing.T) {
+		var meta bytes.Buffer
+		meta.Write(common.FromHex("0xa165627a7a72305820"))
+		meta.Write(swarm)
+
+		runtime, metadata := bytecode.SplitMetadata(withMetadata(code, meta.Bytes()))
+		assert.Equal(t, code, runtime)
+		require.NotNil(t, metadata)
+
+		assert.Empty(t, metadata.CompilerVersion)
+		assert.Equal(t, "bzzr0", metadata.SwarmVersion)
+		assert.Equal(t, swarm, metadata.Swarm)
+		assert.Empty(t, metadata.IPFSHash())
+	})
+
+	t.Run("Experimental", func(t *testing.T) {
+		var meta bytes.Buffer
+		meta.Write(common.FromHex("0xa365627a7a72315820"))
+		meta.Write(swarm)
+		meta.Write(common.FromHex("0x6c6578706572696d656e74616cf5"))
+		meta.Write(common.FromHex("0x64736f6c6343000511"))
+
+		_, metadata := bytecode.SplitMetadata(withMetadata(code, meta.Bytes()))
+		require.NotNil(t, metadata)
+
+		assert.Equal(t, "0.5.17", metadata.CompilerVersion)
+		assert.Equal(t, "bzzr1", metadata.SwarmVersion)
+		assert.True(t, metadata.Experimental)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		runtime, metadata := bytecode.SplitMetadata(code)
+		assert.Equal(t, code, runtime)
+		assert.Nil(t, metadata)
+
+		runtime, metadata = bytecode.SplitMetadata(nil)
+		assert.Empty(t, runtime)
+		assert.Nil(t, metadata)
+	})
+}
+
+func TestIPFSHash(t *testing.T) {
+	meta := bytecode.Metadata{IPFS: common.FromHex("0x0000287fb4cd")}
+	assert.Equal(t, "11233QC4", meta.IPFSHash())
+}
+
+func TestSelectors(t *testing.T) {
+	code := withMetadata(dispatcher(erc20...), common.FromHex("0xa164736f6c6343000813"))
+
+	selectors := bytecode.Selectors(code)
+	require.Len(t, selectors, len(erc20))
+
+	for i, function := range erc20 {
+		assert.Equal(t, selector(function), selectors[i], function)
+	}
+
+	t.Run("ShortPush", func(t *testing.T) {
+		// The ERC-1155 balanceOf selector 0x00fdd58e is pushed with PUSH3.
+		code := dispatcher("balanceOf(address,uint256)")
+		assert.Contains(t, string(code), string(common.FromHex("0x8062fdd58e14")))
+
+		assert.Equal(t, [][4]byte{{0x00, 0xfd, 0xd5, 0x8e}}, bytecode.Selectors(code))
+	})
+
+	t.Run("ConstantCompare", func(t *testing.T) {
+		// Runtime code of the 0x v2 Exchange and Forwarder, as captured in
+		// go-ethereum's tracer test data. Their function bodies
+		// compare constants much as the dispatcher compares selectors:
+		// signature.length == 65, a signature type == 3, a call's
+		// success == 1 and returndatasize() == 32.
+		codes := readCode(t)
+		constants := [][4]byte{{0, 0, 0, 0x41}, {0, 0, 0, 0x03}, {0, 0, 0, 0x01}, {0, 0, 0, 0x20}}
+
+		selectors := bytecode.Selectors(codes[common.HexToAddress("0x48bacb9266a570d521063ef5dd96e61686dbe788")])
+		assert.Len(t, selectors, 35)
+		assert.Subset(t, selectors, [][4]byte{
+			selector("filled(bytes32)"),
+			selector("cancelOrdersUpTo(uint256)"),
+			selector("executeTransaction(uint256,address,bytes,bytes)"),
+			selector("isValidSignature(bytes32,address,bytes)"),
+			selector("fillOrder((address,address,address,address,uint256,uint256,uint256,uint256,uint256,uint256,bytes,bytes),uint256,bytes)"),
+		})
+
+		forwarderSelectors := bytecode.Selectors(codes[common.HexToAddress("0x6000eca38b8b5bba64986182fe2a69c57f6b5414")])
+		assert.Len(t, forwarderSelectors, 5)
+		assert.Contains(t, forwarderSelectors, selector("withdrawAsset(bytes,uint256)"))
+
+		for _, constant := range constants {
+			assert.NotContains(t, selectors, constant)
+			assert.NotContains(t, forwarderSelectors, constant)
+		}
+	})
+
+	t.Run("NoDispatcher", func(t *testing.T) {
+		// PUSH4 selector DUP2 EQ PUSH2 dest JUMPI JUMPDEST, without the
+		// prologue that loads the selector of the call.
+		code := common.FromHex("0x6318160ddd811461000b575b")
+		assert.Empty(t, bytecode.Selectors(code))
+	})
+}
+
+// readCode reads the runtime code of contracts from testData/code.json.
+func readCode(t *testing.T) map[common.Address]hexutil.Bytes {
+	data, err := os.ReadFile(filepath.Join("testData", "code.json"))
+	require.NoError(t, err)
+
+	var codes map[common.Address]hexutil.Bytes
+	require.NoError(t, json.Unmarshal(data, &codes))
+
+	return codes
+}
+
+func TestClassify(t *testing.T) {
+	t.Run("ERC20", func(t *testing.T) {
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardERC20}, bytecode.Classify(dispatcher(erc20...)))
+	})
+
+	t.Run("ERC4626", func(t *testing.T) {
+		code := dispatcher(append(append([]string(nil), erc20...), erc4626...)...)
+		assert.Equal(
+			t,
+			[]bytecode.Standard{bytecode.StandardERC20, bytecode.StandardERC4626},
+			bytecode.Classify(code),
+		)
+	})
+
+	t.Run("ERC721", func(t *testing.T) {
+		code := dispatcher(
+			"balanceOf(address)",
+			"ownerOf(uint256)",
+			"safeTransferFrom(address,address,uint256)",
+			"safeTransferFrom(address,address,uint256,bytes)",
+			"transferFrom(address,address,uint256)",
+			"approve(address,uint256)",
+			"setApprovalForAll(address,bool)",
+			"getApproved(uint256)",
+			"isApprovedForAll(address,address)",
+			"supportsInterface(bytes4)",
+		)
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardERC721}, bytecode.Classify(code))
+	})
+
+	t.Run("ERC1155", func(t *testing.T) {
+		code := dispatcher(
+			"balanceOf(address,uint256)",
+			"balanceOfBatch(address[],uint256[])",
+			"setApprovalForAll(address,bool)",
+			"isApprovedForAll(address,address)",
+			"safeTransferFrom(address,address,uint256,uint256,bytes)",
+			"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		)
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardERC1155}, bytecode.Classify(code))
+	})
+
+	t.Run("Safe", func(t *testing.T) {
+		code := dispatcher(
+			"execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+			"getOwners()",
+			"getThreshold()",
+			"nonce()",
+		)
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardSafe}, bytecode.Classify(code))
+	})
+
+	t.Run("MinimalProxy", func(t *testing.T) {
+		impl := common.HexToAddress("0xbebc44782c7db0a1a60cb6fe97d0b483032ff1c7")
+		code := common.FromHex("0x363d3d373d3d3d363d73bebc44782c7db0a1a60cb6fe97d0b483032ff1c75af43d82803e903d91602b57fd5bf3")
+
+		actual, ok := bytecode.MinimalProxyImplementation(code)
+		require.True(t, ok)
+		assert.Equal(t, impl, actual)
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardProxy}, bytecode.Classify(code))
+	})
+
+	t.Run("EIP1967Proxy", func(t *testing.T) {
+		// PUSH32 slot SLOAD ... DELEGATECALL
+		code := append([]byte{0x7f}, storage.EIP1967ImplementationSlot.Bytes()...)
+		code = append(code, common.FromHex("0x54365f5f375f5f365f845af43d5f5f3e")...)
+
+		assert.True(t, bytecode.IsProxy(code))
+		assert.Equal(t, []bytecode.Standard{bytecode.StandardProxy}, bytecode.Classify(code))
+
+		_, ok := bytecode.MinimalProxyImplementation(code)
+		assert.False(t, ok)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		assert.Empty(t, bytecode.Classify(dispatcher("deposit()", "withdraw(uint256)")))
+		assert.False(t, bytecode.IsProxy(dispatcher(erc20...)))
+	})
+}
@@ -0,0 +1,107 @@
+package bytecode
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Standard is a contract standard that bytecode can be classified against.
+type Standard string
+
+const (
+	StandardERC20   Standard = "ERC-20"
+	StandardERC721  Standard = "ERC-721"
+	StandardERC1155 Standard = "ERC-1155"
+	StandardERC4626 Standard = "ERC-4626"
+	StandardSafe    Standard = "Safe"
+	StandardProxy   Standard = "proxy"
+)
+
+// standards lists the functions that a contract must implement to be
+// classified as each standard, in the order standards are reported.
+var standards = []struct {
+	standard  Standard
+	functions []string
+}{
+	{StandardERC20, []string{
+		"totalSupply()",
+		"balanceOf(address)",
+		"transfer(address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"allowance(address,address)",
+	}},
+	{StandardERC721, []string{
+		"balanceOf(address)",
+		"ownerOf(uint256)",
+		"safeTransferFrom(address,address,uint256)",
+		"safeTransferFrom(address,address,uint256,bytes)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"setApprovalForAll(address,bool)",
+		"getApproved(uint256)",
+		"isApprovedForAll(address,address)",
+	}},
+	{StandardERC1155, []string{
+		"balanceOf(address,uint256)",
+		"balanceOfBatch(address[],uint256[])",
+		"setApprovalForAll(address,bool)",
+		"isApprovedForAll(address,address)",
+		"safeTransferFrom(address,address,uint256,uint256,bytes)",
+		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+	}},
+	{StandardERC4626, []string{
+		"asset()",
+		"totalAssets()",
+		"convertToShares(uint256)",
+		"convertToAssets(uint256)",
+		"deposit(uint256,address)",
+		"mint(uint256,address)",
+		"withdraw(uint256,address,address)",
+		"redeem(uint256,address,address)",
+	}},
+	{StandardSafe, []string{
+		"execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+		"getOwners()",
+		"getThreshold()",
+		"nonce()",
+	}},
+}
+
+// Classify returns the standards that runtime bytecode implements, judged by
+// the function selectors in its dispatcher. A contract may implement several,
+// e.g. an ERC-4626 vault is also an ERC-20 token. Proxies are recognised
+// from their code, as the standards they implement are those of their
+// implementation.
+func Classify(code []byte) []Standard {
+	var result []Standard
+	if IsProxy(code) {
+		result = append(result, StandardProxy)
+	}
+
+	selectors := make(map[[4]byte]bool)
+	for _, selector := range Selectors(code) {
+		selectors[selector] = true
+	}
+
+	for _, s := range standards {
+		if implements(selectors, s.functions) {
+			result = append(result, s.standard)
+		}
+	}
+
+	return result
+}
+
+func implements(selectors map[[4]byte]bool, functions []string) bool {
+	for _, function := range functions {
+		if !selectors[selector(function)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func selector(signature string) [4]byte {
+	return [4]byte(crypto.Keccak256([]byte(signature))[:4])
+}
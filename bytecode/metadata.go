@@ -0,0 +1,291 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata is the CBOR-encoded metadata that the Solidity compiler appends
+// to runtime bytecode.
+type Metadata struct {
+	// CompilerVersion is the solc version, e.g. "0.8.19". It is empty for
+	// compilers that predate its inclusion.
+	CompilerVersion string
+	// IPFS is the multihash of the metadata file on IPFS, if present.
+	IPFS []byte
+	// Swarm is the hash of the metadata file on Swarm, if present.
+	Swarm []byte
+	// SwarmVersion is the key under which the Swarm hash is stored, either
+	// "bzzr0" or "bzzr1".
+	SwarmVersion string
+	// Experimental is set if the contract uses experimental features.
+	Experimental bool
+	// Raw is the metadata trailer, including its two-byte length suffix.
+	Raw []byte
+}
+
+// IPFSHash returns the base58-encoded IPFS hash of the metadata file, e.g.
+// "QmX...", or an empty string if there is none.
+func (m *Metadata) IPFSHash() string {
+	if len(m.IPFS) == 0 {
+		return ""
+	}
+
+	return base58Encode(m.IPFS)
+}
+
+// SplitMetadata splits runtime bytecode into the executable code and the
+// metadata trailer. If the code has no recognisable trailer, the code is
+// returned unchanged with nil metadata.
+func SplitMetadata(code []byte) ([]byte, *Metadata) {
+	if len(code) < 2 {
+		return code, nil
+	}
+
+	length := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - length
+	if length == 0 || start < 0 {
+		return code, nil
+	}
+
+	meta, err := parseMetadata(code[start : len(code)-2])
+	if err != nil {
+		return code, nil
+	}
+
+	meta.Raw = code[start:]
+
+	return code[:start], meta
+}
+
+func parseMetadata(data []byte) (*Metadata, error) {
+	d := cborDecoder{data: data}
+
+	value, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(data) {
+		return nil, errors.New("trailing data after metadata")
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("metadata is not a map")
+	}
+
+	meta := new(Metadata)
+
+	for key, value := range fields {
+		switch key {
+		case "solc":
+			switch version := value.(type) {
+			case []byte:
+				if len(version) != 3 {
+					return nil, errors.Errorf("invalid compiler version %x", version)
+				}
+
+				meta.CompilerVersion = fmt.Sprintf("%d.%d.%d", version[0], version[1], version[2])
+
+			case string:
+				// Pre-release builds store the full version string.
+				meta.CompilerVersion = version
+			}
+
+		case "ipfs":
+			meta.IPFS, _ = value.([]byte)
+
+		case "bzzr0", "bzzr1":
+			meta.Swarm, _ = value.([]byte)
+			meta.SwarmVersion = key
+
+		case "experimental":
+			meta.Experimental, _ = value.(bool)
+		}
+	}
+
+	return meta, nil
+}
+
+// cborDecoder decodes the subset of CBOR used by compiler metadata: integers,
+// byte and text strings, arrays, maps with text keys and simple values.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+const (
+	cborUint   = 0
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborSimple = 7
+)
+
+// maxCBORDepth bounds the nesting of arrays and maps.
+const maxCBORDepth = 8
+
+func (d *cborDecoder) decode() (interface{}, error) {
+	return d.decodeDepth(0)
+}
+
+func (d *cborDecoder) decodeDepth(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("CBOR nested too deeply")
+	}
+
+	if d.pos >= len(d.data) {
+		return nil, errors.New("unexpected end of CBOR data")
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+
+	major, info := initial>>5, initial&0x1f
+
+	if major == cborSimple {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		default:
+			return nil, errors.Errorf("unsupported CBOR simple value %d", info)
+		}
+	}
+
+	arg, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return arg, nil
+
+	case cborBytes, cborText:
+		raw, err := d.read(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if major == cborText {
+			return string(raw), nil
+		}
+
+		return raw, nil
+
+	case cborArray:
+		if arg > uint64(len(d.data)) {
+			return nil, errors.New("CBOR array too long")
+		}
+
+		values := make([]interface{}, arg)
+		for i := range values {
+			if values[i], err = d.decodeDepth(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+
+	case cborMap:
+		if arg > uint64(len(d.data)) {
+			return nil, errors.New("CBOR map too long")
+		}
+
+		values := make(map[string]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.decodeDepth(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, errors.New("CBOR map key is not a string")
+			}
+
+			if values[keyStr], err = d.decodeDepth(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+
+	default:
+		return nil, errors.Errorf("unsupported CBOR major type %d", major)
+	}
+}
+
+// argument decodes the argument of a data item: an integer value, or the
+// length of a string, array or map.
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+
+	case info <= 27:
+		raw, err := d.read(1 << (info - 24))
+		if err != nil {
+			return 0, err
+		}
+
+		var arg uint64
+		for _, b := range raw {
+			arg = arg<<8 | uint64(b)
+		}
+
+		return arg, nil
+
+	default:
+		return 0, errors.Errorf("unsupported CBOR argument %d", info)
+	}
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of CBOR data")
+	}
+
+	raw := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+
+	return raw, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+
+	// Leading zero bytes are encoded as leading '1's.
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
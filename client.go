@@ -39,7 +39,7 @@ func New(params *Params) *Client {
 		Transactions: transactions.TransactionsClient{API: api},
 		Blocks:       blocks.BlocksClient{API: api},
 		Logs:         logs.LogsClient{API: api},
-		Proxy:        proxy.ProxyClient{API: api},
+		Proxy:        proxy.ProxyClient{API: api, Node: params.Node, Policy: params.ProviderPolicy},
 		Tokens:       tokens.TokensClient{API: api},
 		Gas:          gas.GasClient{API: api},
 		Stats:        stats.StatsClient{API: api},
@@ -0,0 +1,52 @@
+// Command etherscan-rpc serves the Ethereum JSON-RPC API over HTTP, backed
+// by Etherscan. The API key is read from the ETHERSCAN_API_KEY environment
+// variable unless given with -api-key.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/rpcserver"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8545", "address to listen on")
+	apiKey := flag.String("api-key", os.Getenv("ETHERSCAN_API_KEY"), "Etherscan API key")
+	chainID := flag.Uint64("chain-id", 1, "chain ID of the network to serve")
+	cacheSize := flag.Int("cache-size", 4096, "maximum number of cached results, or 0 to disable caching")
+	cacheTTL := flag.Duration("cache-ttl", 12*time.Second, "duration for which results are cached")
+	rateLimit := flag.Float64("rate-limit", 5, "maximum Etherscan calls per second, or 0 for no limit")
+	rateBurst := flag.Int("rate-burst", 1, "number of Etherscan calls that may be made at once")
+	flag.Parse()
+
+	if *apiKey == "" {
+		log.Fatal().Msg("an Etherscan API key is required")
+	}
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  *apiKey,
+		ChainID: *chainID,
+	})
+
+	srv := rpcserver.New(&rpcserver.Params{
+		Proxy:     &client.Proxy,
+		Accounts:  &client.Accounts,
+		Logs:      &client.Logs,
+		ChainID:   *chainID,
+		CacheSize: *cacheSize,
+		CacheTTL:  *cacheTTL,
+		RateLimit: *rateLimit,
+		RateBurst: *rateBurst,
+	})
+
+	log.Info().Str("addr", *addr).Uint64("chainID", *chainID).Msg("serving JSON-RPC")
+
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		log.Fatal().Err(err).Msg("server failed")
+	}
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// ErrHashTagUnsupported is returned when a block hash tag is sent to
+// Etherscan, which only accepts block numbers and named tags.
+var ErrHashTagUnsupported = errors.New("block hash tags are only supported by a node provider")
+
+// BlockTag identifies the block at which state is read. It is either a named
+// block parameter, a block number or a block hash. The zero value is the
+// latest block.
+type BlockTag struct {
+	param     BlockParameter
+	number    uint64
+	hash      ethcommon.Hash
+	hasNumber bool
+	hasHash   bool
+}
+
+// BlockTagNamed returns a BlockTag for a named block parameter.
+func BlockTagNamed(param BlockParameter) BlockTag {
+	return BlockTag{param: param}
+}
+
+// BlockTagNumber returns a BlockTag for a block number.
+func BlockTagNumber(number uint64) BlockTag {
+	return BlockTag{number: number, hasNumber: true}
+}
+
+// BlockTagHash returns a BlockTag for a block hash. Hash tags can only be
+// served by a node Provider, as EIP-1898 objects: Etherscan does not accept
+// them, and calls it would serve fail with ErrHashTagUnsupported.
+func BlockTagHash(hash ethcommon.Hash) BlockTag {
+	return BlockTag{hash: hash, hasHash: true}
+}
+
+// ParseBlockTag parses a named block parameter, a hex or decimal block number
+// or a block hash.
+func ParseBlockTag(s string) (BlockTag, error) {
+	if param, err := ParseBlockParameter(s); err == nil {
+		return BlockTagNamed(param), nil
+	}
+
+	if strings.HasPrefix(s, "0x") {
+		if len(s) == 2+2*ethcommon.HashLength {
+			hash, err := hexutil.Decode(s)
+			if err != nil {
+				return BlockTag{}, errors.Wrapf(err, "invalid block hash %s", s)
+			}
+
+			return BlockTagHash(ethcommon.BytesToHash(hash)), nil
+		}
+
+		number, err := hexutil.DecodeUint64(s)
+		if err != nil {
+			return BlockTag{}, errors.Wrapf(err, "invalid block number %s", s)
+		}
+
+		return BlockTagNumber(number), nil
+	}
+
+	number, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return BlockTag{}, errors.Errorf("%s is not a valid BlockTag", s)
+	}
+
+	return BlockTagNumber(number), nil
+}
+
+// Param returns the named block parameter, if the tag is one.
+func (t BlockTag) Param() (BlockParameter, bool) {
+	return t.param, !t.hasNumber && !t.hasHash
+}
+
+// Number returns the block number, if the tag is one.
+func (t BlockTag) Number() (uint64, bool) {
+	return t.number, t.hasNumber
+}
+
+// Hash returns the block hash, if the tag is one.
+func (t BlockTag) Hash() (ethcommon.Hash, bool) {
+	return t.hash, t.hasHash
+}
+
+// String returns the tag as used by JSON-RPC methods, with block numbers
+// encoded as hex.
+func (t BlockTag) String() string {
+	switch {
+	case t.hasNumber:
+		return hexutil.EncodeUint64(t.number)
+
+	case t.hasHash:
+		return t.hash.Hex()
+
+	default:
+		return t.param.String()
+	}
+}
+
+type blockHashParam struct {
+	BlockHash ethcommon.Hash `json:"blockHash"`
+}
+
+// MarshalJSON encodes the tag as a JSON-RPC block parameter. Block hashes are
+// encoded as an EIP-1898 object.
+func (t BlockTag) MarshalJSON() ([]byte, error) {
+	if t.hasHash {
+		return json.Marshal(blockHashParam{BlockHash: t.hash})
+	}
+
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a JSON-RPC block parameter, including EIP-1898
+// objects.
+func (t *BlockTag) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		tag, err := ParseBlockTag(s)
+		if err != nil {
+			return err
+		}
+
+		*t = tag
+		return nil
+	}
+
+	var obj struct {
+		BlockHash   *ethcommon.Hash `json:"blockHash"`
+		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	switch {
+	case obj.BlockHash != nil:
+		*t = BlockTagHash(*obj.BlockHash)
+
+	case obj.BlockNumber != nil:
+		*t = BlockTagNumber(uint64(*obj.BlockNumber))
+
+	default:
+		return errors.New("block parameter object has no blockHash or blockNumber")
+	}
+
+	return nil
+}
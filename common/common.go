@@ -1,7 +1,11 @@
 //go:generate go-enum -f=$GOFILE
 package common
 
-import "time"
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
 
 const (
 	AccountsModule  = "account"
@@ -24,6 +28,29 @@ type DateRange struct {
 	Sort      SortingPreference
 }
 
+// Pagination contains the optional page number and page size parameters for
+// requests that return paginated results. It is embedded in request structs.
+type Pagination struct {
+	Page   uint64 `etherscan:"page,omitempty"`
+	Offset uint64 `etherscan:"offset,omitempty"`
+}
+
+// ExtraFields holds the fields of a response record that are not decoded
+// into the record's other fields, keyed by name.
+type ExtraFields map[string]json.RawMessage
+
 // BlockParameter is an enumeration of allowed block parameters.
-// ENUM(latest,earliest,pending)
+// ENUM(latest,earliest,pending,safe,finalized)
 type BlockParameter int32
+
+// Provider is a JSON-RPC endpoint that can serve proxy methods directly,
+// such as an Ethereum node. It is satisfied by *rpc.Client from
+// go-ethereum's rpc package.
+type Provider interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// ProviderPolicy is an enumeration of policies for choosing between
+// Etherscan and a node Provider.
+// ENUM(etherscan-only,node-only,etherscan-first,node-first)
+type ProviderPolicy int32
@@ -17,14 +17,20 @@ const (
 	BlockParameterEarliest
 	// BlockParameterPending is a BlockParameter of type Pending.
 	BlockParameterPending
+	// BlockParameterSafe is a BlockParameter of type Safe.
+	BlockParameterSafe
+	// BlockParameterFinalized is a BlockParameter of type Finalized.
+	BlockParameterFinalized
 )
 
-const _BlockParameterName = "latestearliestpending"
+const _BlockParameterName = "latestearliestpendingsafefinalized"
 
 var _BlockParameterMap = map[BlockParameter]string{
 	0: _BlockParameterName[0:6],
 	1: _BlockParameterName[6:14],
 	2: _BlockParameterName[14:21],
+	3: _BlockParameterName[21:25],
+	4: _BlockParameterName[25:34],
 }
 
 // String implements the Stringer interface.
@@ -39,6 +45,8 @@ var _BlockParameterValue = map[string]BlockParameter{
 	_BlockParameterName[0:6]:   0,
 	_BlockParameterName[6:14]:  1,
 	_BlockParameterName[14:21]: 2,
+	_BlockParameterName[21:25]: 3,
+	_BlockParameterName[25:34]: 4,
 }
 
 // ParseBlockParameter attempts to convert a string to a BlockParameter
@@ -49,6 +57,49 @@ func ParseBlockParameter(name string) (BlockParameter, error) {
 	return BlockParameter(0), fmt.Errorf("%s is not a valid BlockParameter", name)
 }
 
+const (
+	// ProviderPolicyEtherscanOnly is a ProviderPolicy of type Etherscan-Only.
+	ProviderPolicyEtherscanOnly ProviderPolicy = iota
+	// ProviderPolicyNodeOnly is a ProviderPolicy of type Node-Only.
+	ProviderPolicyNodeOnly
+	// ProviderPolicyEtherscanFirst is a ProviderPolicy of type Etherscan-First.
+	ProviderPolicyEtherscanFirst
+	// ProviderPolicyNodeFirst is a ProviderPolicy of type Node-First.
+	ProviderPolicyNodeFirst
+)
+
+const _ProviderPolicyName = "etherscan-onlynode-onlyetherscan-firstnode-first"
+
+var _ProviderPolicyMap = map[ProviderPolicy]string{
+	0: _ProviderPolicyName[0:14],
+	1: _ProviderPolicyName[14:23],
+	2: _ProviderPolicyName[23:38],
+	3: _ProviderPolicyName[38:48],
+}
+
+// String implements the Stringer interface.
+func (x ProviderPolicy) String() string {
+	if str, ok := _ProviderPolicyMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ProviderPolicy(%d)", x)
+}
+
+var _ProviderPolicyValue = map[string]ProviderPolicy{
+	_ProviderPolicyName[0:14]:  0,
+	_ProviderPolicyName[14:23]: 1,
+	_ProviderPolicyName[23:38]: 2,
+	_ProviderPolicyName[38:48]: 3,
+}
+
+// ParseProviderPolicy attempts to convert a string to a ProviderPolicy
+func ParseProviderPolicy(name string) (ProviderPolicy, error) {
+	if x, ok := _ProviderPolicyValue[name]; ok {
+		return x, nil
+	}
+	return ProviderPolicy(0), fmt.Errorf("%s is not a valid ProviderPolicy", name)
+}
+
 const (
 	// SortingPreferenceAsc is a SortingPreference of type Asc.
 	SortingPreferenceAsc SortingPreference = iota
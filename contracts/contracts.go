@@ -2,18 +2,31 @@ package contracts
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/ryanc414/etherscan-api-go/httpapi"
 )
 
+// ErrNotVerified is returned for contracts whose source code is not verified.
+var ErrNotVerified = errors.New("contract source code not verified")
+
+// notVerifiedMessage is returned by Etherscan in place of the ABI of a
+// contract that is not verified.
+const notVerifiedMessage = "Contract source code not verified"
+
 // ContractsClient is the client for contracts actions.
 type ContractsClient struct {
 	API *httpapi.APIClient
 }
 
-// GetContractABI returns the contract ABI as a JSON string.
+// GetContractABI returns the contract ABI as a JSON string. ErrNotVerified
+// is returned if the contract is not verified.
 func (c ContractsClient) GetContractABI(
 	ctx context.Context, address common.Address,
 ) (result string, err error) {
@@ -25,24 +38,104 @@ func (c ContractsClient) GetContractABI(
 		Result:  &result,
 	})
 
+	if msg, ok := httpapi.ResultMessage(err); ok && msg == notVerifiedMessage {
+		return "", errors.Wrapf(ErrNotVerified, "contract %s", address)
+	}
+
 	return result, err
 }
 
-// ContractInfo contains information on a contract's source code.
+// ContractInfo contains information on a contract's source code. The
+// Implementation of a contract that is not a proxy is nil.
 type ContractInfo struct {
-	SourceCode           string `etherscan:"SourceCode"`
-	ABI                  string `etherscan:"ABI"`
-	ContractName         string `etherscan:"ContractName"`
-	CompilerVersion      string `etherscan:"CompilerVersion"`
-	OptimizationUsed     string `etherscan:"OptimizationUsed"`
-	Runs                 uint32 `etherscan:"Runs"`
-	ConstructorArguments string `etherscan:"ConstructorArguments"`
-	EVMVersion           string `etherscan:"EVMVersion"`
-	Library              string `etherscan:"Library"`
-	LicenseType          string `etherscan:"LicenseType"`
-	Proxy                bool   `etherscan:"Proxy,num"`
-	Implementation       string `etherscan:"Implementation"`
-	SwarmSource          string `etherscan:"SwarmSource"`
+	SourceCode           string          `etherscan:"SourceCode"`
+	ABI                  string          `etherscan:"ABI"`
+	ContractName         string          `etherscan:"ContractName"`
+	CompilerVersion      string          `etherscan:"CompilerVersion"`
+	OptimizationUsed     bool            `etherscan:"OptimizationUsed,num"`
+	Runs                 uint32          `etherscan:"Runs"`
+	ConstructorArguments string          `etherscan:"ConstructorArguments"`
+	EVMVersion           string          `etherscan:"EVMVersion"`
+	Library              Libraries       `etherscan:"Library"`
+	LicenseType          string          `etherscan:"LicenseType"`
+	Proxy                bool            `etherscan:"Proxy,num"`
+	Implementation       *common.Address `etherscan:"Implementation"`
+	SwarmSource          string          `etherscan:"SwarmSource"`
+
+	// Extra holds any response fields not decoded into the fields above,
+	// such as newer verification details.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
+}
+
+// Verified returns whether the contract's source code is verified.
+func (info *ContractInfo) Verified() bool {
+	return info.ABI != "" && info.ABI != notVerifiedMessage
+}
+
+// ParseABI parses the contract's ABI.
+func (info *ContractInfo) ParseABI() (abi.ABI, error) {
+	if !info.Verified() {
+		return abi.ABI{}, ErrNotVerified
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(info.ABI))
+	if err != nil {
+		return abi.ABI{}, errors.Wrap(err, "while parsing ABI")
+	}
+
+	return parsed, nil
+}
+
+// ConstructorArgs decodes the arguments the contract was deployed with, in
+// the order of the constructor's inputs, using the contract's ABI. Values
+// are of the types returned by go-ethereum's abi package, e.g. a *big.Int
+// for a uint256.
+func (info *ContractInfo) ConstructorArgs() ([]interface{}, error) {
+	parsed, err := info.ParseABI()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(info.ConstructorArguments, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "while decoding constructor arguments")
+	}
+
+	values, err := parsed.Constructor.Inputs.UnpackValues(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unpacking constructor arguments")
+	}
+
+	return values, nil
+}
+
+// Libraries maps library names to the addresses they are linked to. It is
+// returned by Etherscan as "Name:address" pairs separated by semicolons.
+type Libraries map[string]common.Address
+
+func (l *Libraries) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	libraries := make(Libraries)
+	for _, pair := range strings.Split(raw, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, address, ok := strings.Cut(pair, ":")
+		if !ok || !common.IsHexAddress(address) {
+			return errors.Errorf("invalid library %q", pair)
+		}
+
+		libraries[name] = common.HexToAddress(address)
+	}
+
+	*l = libraries
+	return nil
 }
 
 // GetContractSourceCode returns the Solidity source code of a verified smart contract.
@@ -2,12 +2,16 @@ package contracts_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/contracts"
 	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,4 +47,123 @@ func TestContracts(t *testing.T) {
 
 		cupaloy.SnapshotT(t, info)
 	})
+
+	t.Run("GetContractABINotVerified", func(t *testing.T) {
+		_, err := client.Contracts.GetContractABI(
+			ctx, common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"),
+		)
+		assert.ErrorIs(t, err, contracts.ErrNotVerified)
+	})
+
+	t.Run("ParseABI", func(t *testing.T) {
+		info, err := client.Contracts.GetContractSourceCode(
+			ctx, common.HexToAddress("0xBB9bc244D798123fDe783fCc1C72d3Bb8C189413"),
+		)
+		require.NoError(t, err)
+
+		parsed, err := info[0].ParseABI()
+		require.NoError(t, err)
+		assert.Contains(t, parsed.Methods, "proposals")
+
+		args, err := info[0].ConstructorArgs()
+		require.NoError(t, err)
+		cupaloy.SnapshotT(t, args)
+	})
+
+	t.Run("NotVerified", func(t *testing.T) {
+		info, err := client.Contracts.GetContractSourceCode(
+			ctx, common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"),
+		)
+		require.NoError(t, err)
+		require.Len(t, info, 1)
+
+		assert.False(t, info[0].Verified())
+
+		_, err = info[0].ParseABI()
+		assert.ErrorIs(t, err, contracts.ErrNotVerified)
+
+		_, err = info[0].ConstructorArgs()
+		assert.ErrorIs(t, err, contracts.ErrNotVerified)
+
+		_, err = info[0].SourceBundle()
+		assert.ErrorIs(t, err, contracts.ErrNotVerified)
+	})
+
+	getSourceBundle := func(t *testing.T, address string) *contracts.SourceBundle {
+		info, err := client.Contracts.GetContractSourceCode(ctx, common.HexToAddress(address))
+		require.NoError(t, err)
+		require.Len(t, info, 1)
+
+		bundle, err := info[0].SourceBundle()
+		require.NoError(t, err)
+
+		return bundle
+	}
+
+	t.Run("SourceBundleSingleFile", func(t *testing.T) {
+		bundle := getSourceBundle(t, "0xBB9bc244D798123fDe783fCc1C72d3Bb8C189413")
+
+		assert.Equal(t, contracts.LanguageSolidity, bundle.Language)
+		assert.Len(t, bundle.Files, 1)
+		assert.Contains(t, bundle.Files, "DAO.sol")
+		assert.JSONEq(t, `{
+			"optimizer": {"enabled": true, "runs": 200},
+			"outputSelection": {"*": {"*": ["abi", "evm.bytecode", "evm.deployedBytecode", "metadata"]}}
+		}`, string(bundle.Settings))
+	})
+
+	t.Run("SourceBundleMultiFile", func(t *testing.T) {
+		bundle := getSourceBundle(t, "0x3d9819210A31b4961b30EF54bE2aeD79B9c9Cd3B")
+		cupaloy.SnapshotT(t, bundle.Language, bundle.Files, bundle.Remappings, bundle.Libraries)
+
+		assert.JSONEq(t, `{
+			"libraries": {"": {"SafeMath": "0xa2c8f46db35e3f4f2e4a59ae0e3fdfe1c1a8b2d3"}},
+			"optimizer": {"enabled": false, "runs": 200},
+			"outputSelection": {"*": {"*": ["abi", "evm.bytecode", "evm.deployedBytecode", "metadata"]}}
+		}`, string(bundle.Settings))
+
+		assert.Equal(t, map[string]common.Address{
+			"SafeMath": common.HexToAddress("0xa2c8f46db35e3f4f2e4a59ae0e3fdfe1c1a8b2d3"),
+		}, bundle.Libraries[""])
+	})
+
+	t.Run("SourceBundleStandardJSON", func(t *testing.T) {
+		bundle := getSourceBundle(t, "0x1F98431c8aD98523631AE4a59f267346ea31F984")
+		cupaloy.SnapshotT(t, bundle.Language, bundle.Files, bundle.Remappings, bundle.Libraries)
+
+		assert.JSONEq(t, `{
+			"remappings": ["@openzeppelin/=lib/openzeppelin-contracts/"],
+			"optimizer": {"enabled": true, "runs": 10000},
+			"evmVersion": "paris",
+			"outputSelection": {"*": {"*": ["abi", "evm.bytecode", "evm.deployedBytecode", "metadata"]}},
+			"libraries": {"contracts/libraries/Math.sol": {"Math": "0x5f3b5dfeb7b28cdbd7faba78963ee202a494e2a2"}}
+		}`, string(bundle.Settings))
+
+		input, err := bundle.StandardJSON()
+		require.NoError(t, err)
+
+		info, err := client.Contracts.GetContractSourceCode(
+			ctx, common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
+		)
+		require.NoError(t, err)
+
+		source := info[0].SourceCode
+		assert.JSONEq(t, source[1:len(source)-1], string(input))
+
+		dir := t.TempDir()
+		require.NoError(t, bundle.WriteFiles(dir))
+
+		content, err := os.ReadFile(filepath.Join(dir, "contracts", "libraries", "Math.sol"))
+		require.NoError(t, err)
+		assert.Equal(t, bundle.Files["contracts/libraries/Math.sol"], string(content))
+	})
+
+	t.Run("WriteFilesConfined", func(t *testing.T) {
+		bundle := &contracts.SourceBundle{Files: map[string]string{"../../escape.sol": "contract A {}"}}
+		dir := t.TempDir()
+		require.NoError(t, bundle.WriteFiles(dir))
+
+		_, err := os.Stat(filepath.Join(dir, "escape.sol"))
+		assert.NoError(t, err)
+	})
 }
@@ -0,0 +1,256 @@
+package contracts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	LanguageSolidity = "Solidity"
+	LanguageVyper    = "Vyper"
+)
+
+// SourceBundle is the parsed source code of a verified contract.
+type SourceBundle struct {
+	// Language is the source language, e.g. LanguageSolidity.
+	Language string
+	// Files maps source file paths to their contents.
+	Files map[string]string
+	// Settings is the compiler settings object of the standard-JSON input.
+	Settings json.RawMessage
+	// Remappings are the import remappings, e.g. "@openzeppelin/=lib/oz/".
+	Remappings []string
+	// Libraries maps source file paths, then library names, to the addresses
+	// of linked libraries. An empty file path applies to all files.
+	Libraries map[string]map[string]common.Address
+}
+
+// standardJSONInput is the standard-JSON input to the compiler.
+type standardJSONInput struct {
+	Language string                `json:"language"`
+	Sources  map[string]sourceFile `json:"sources"`
+	Settings json.RawMessage       `json:"settings,omitempty"`
+}
+
+type sourceFile struct {
+	Content string `json:"content"`
+}
+
+// standardJSONSettings holds the settings needed to populate a SourceBundle.
+type standardJSONSettings struct {
+	Remappings []string                     `json:"remappings"`
+	Libraries  map[string]map[string]string `json:"libraries"`
+}
+
+// SourceBundle parses the contract's source code, which may be a single file,
+// a JSON object of files or a standard-JSON input. Settings are derived from
+// the other contract fields if the source code does not include them.
+func (info *ContractInfo) SourceBundle() (*SourceBundle, error) {
+	source := strings.TrimSpace(info.SourceCode)
+	if source == "" {
+		return nil, ErrNotVerified
+	}
+
+	// Standard-JSON input is wrapped in an extra pair of braces.
+	if strings.HasPrefix(source, "{{") && strings.HasSuffix(source, "}}") {
+		source = source[1 : len(source)-1]
+	}
+
+	if !strings.HasPrefix(source, "{") {
+		return info.singleFileBundle(source)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(source), &fields); err != nil {
+		return nil, errors.Wrap(err, "while parsing source code JSON")
+	}
+
+	if _, ok := fields["sources"]; ok {
+		return parseStandardJSON([]byte(source))
+	}
+
+	var files map[string]sourceFile
+	if err := json.Unmarshal([]byte(source), &files); err != nil {
+		return nil, errors.Wrap(err, "while parsing source files")
+	}
+
+	return info.bundle(files)
+}
+
+func (info *ContractInfo) singleFileBundle(source string) (*SourceBundle, error) {
+	name := info.ContractName
+	if name == "" {
+		name = "Contract"
+	}
+
+	if info.language() == LanguageVyper {
+		name += ".vy"
+	} else {
+		name += ".sol"
+	}
+
+	return info.bundle(map[string]sourceFile{name: {Content: source}})
+}
+
+// bundle constructs a SourceBundle for source files without settings.
+func (info *ContractInfo) bundle(files map[string]sourceFile) (*SourceBundle, error) {
+	bundle := &SourceBundle{
+		Language:   info.language(),
+		Files:      make(map[string]string, len(files)),
+		Remappings: []string{},
+		Libraries:  make(map[string]map[string]common.Address),
+	}
+
+	for path, file := range files {
+		bundle.Files[path] = file.Content
+	}
+
+	if len(info.Library) > 0 {
+		bundle.Libraries[""] = info.Library
+	}
+
+	var err error
+	bundle.Settings, err = json.Marshal(info.settings(bundle.Libraries))
+	if err != nil {
+		return nil, errors.Wrap(err, "while encoding compiler settings")
+	}
+
+	return bundle, nil
+}
+
+// settings derives the compiler settings from the contract fields.
+func (info *ContractInfo) settings(libraries map[string]map[string]common.Address) map[string]interface{} {
+	settings := map[string]interface{}{
+		"optimizer": map[string]interface{}{
+			"enabled": info.OptimizationUsed,
+			"runs":    info.Runs,
+		},
+		"outputSelection": map[string]interface{}{
+			"*": map[string][]string{
+				"*": {"abi", "evm.bytecode", "evm.deployedBytecode", "metadata"},
+			},
+		},
+	}
+
+	if info.EVMVersion != "" && !strings.EqualFold(info.EVMVersion, "default") {
+		settings["evmVersion"] = strings.ToLower(info.EVMVersion)
+	}
+
+	if len(libraries) > 0 {
+		settings["libraries"] = libraries
+	}
+
+	return settings
+}
+
+func (info *ContractInfo) language() string {
+	if strings.HasPrefix(strings.ToLower(info.CompilerVersion), "vyper") {
+		return LanguageVyper
+	}
+
+	return LanguageSolidity
+}
+
+func parseStandardJSON(source []byte) (*SourceBundle, error) {
+	var input standardJSONInput
+	if err := json.Unmarshal(source, &input); err != nil {
+		return nil, errors.Wrap(err, "while parsing standard-JSON input")
+	}
+
+	bundle := &SourceBundle{
+		Language:   input.Language,
+		Files:      make(map[string]string, len(input.Sources)),
+		Settings:   input.Settings,
+		Remappings: []string{},
+		Libraries:  make(map[string]map[string]common.Address),
+	}
+
+	if bundle.Language == "" {
+		bundle.Language = LanguageSolidity
+	}
+
+	for path, file := range input.Sources {
+		bundle.Files[path] = file.Content
+	}
+
+	if len(input.Settings) == 0 {
+		return bundle, nil
+	}
+
+	var settings standardJSONSettings
+	if err := json.Unmarshal(input.Settings, &settings); err != nil {
+		return nil, errors.Wrap(err, "while parsing compiler settings")
+	}
+
+	if settings.Remappings != nil {
+		bundle.Remappings = settings.Remappings
+	}
+
+	for path, libraries := range settings.Libraries {
+		bundle.Libraries[path] = make(map[string]common.Address, len(libraries))
+		for name, address := range libraries {
+			if !common.IsHexAddress(address) {
+				return nil, errors.Errorf("invalid address %q for library %s", address, name)
+			}
+
+			bundle.Libraries[path][name] = common.HexToAddress(address)
+		}
+	}
+
+	return bundle, nil
+}
+
+// StandardJSON returns the standard-JSON input to compile the source code.
+func (b *SourceBundle) StandardJSON() ([]byte, error) {
+	input := standardJSONInput{
+		Language: b.Language,
+		Sources:  make(map[string]sourceFile, len(b.Files)),
+		Settings: b.Settings,
+	}
+
+	for path, content := range b.Files {
+		input.Sources[path] = sourceFile{Content: content}
+	}
+
+	return json.Marshal(input)
+}
+
+// WriteFiles writes the source files to a directory, creating it and any
+// subdirectories as needed.
+func (b *SourceBundle) WriteFiles(dir string) error {
+	for path, content := range b.Files {
+		target, err := sourcePath(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return errors.Wrapf(err, "while creating directory for %s", path)
+		}
+
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			return errors.Wrapf(err, "while writing %s", path)
+		}
+	}
+
+	return nil
+}
+
+// sourcePath returns the path to write a source file to under dir. Paths are
+// confined to dir: absolute paths are made relative and references to parent
+// directories beyond the root are dropped.
+func sourcePath(dir, path string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash("/" + path))
+	rel = strings.TrimPrefix(rel, string(filepath.Separator))
+
+	if rel == "" || rel == "." {
+		return "", errors.Errorf("invalid source path %q", path)
+	}
+
+	return filepath.Join(dir, rel), nil
+}
@@ -0,0 +1,141 @@
+// Package decode decodes event logs and transaction input using the ABIs of
+// verified contracts, fetched from the contracts module. Calls to proxies are
+// decoded with the ABI of their implementation, and a bundled table of
+// common signatures is used when a contract's ABI is unavailable.
+package decode
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanc414/etherscan-api-go/contracts"
+)
+
+// Decoder decodes logs and transaction input. ABIs are cached for the
+// lifetime of the Decoder.
+type Decoder struct {
+	contracts *contracts.ContractsClient
+
+	mu   sync.Mutex
+	abis map[common.Address]contractABI
+}
+
+// contractABI holds the ABIs used to decode a contract's logs and input, in
+// order of precedence: the implementation's for a proxy, then the
+// contract's own. It is empty if the contract's ABI is unavailable.
+type contractABI []*abi.ABI
+
+// NewDecoder constructs a new Decoder that fetches ABIs with the contracts
+// client.
+func NewDecoder(client *contracts.ContractsClient) *Decoder {
+	return &Decoder{
+		contracts: client,
+		abis:      make(map[common.Address]contractABI),
+	}
+}
+
+// contractABI returns the ABIs for a contract, fetching them if they are not
+// cached. Contracts that are not verified are cached as having no ABI, while
+// ABIs that cannot be fetched for other reasons are not cached, so that
+// transient errors are retried.
+func (d *Decoder) contractABI(ctx context.Context, address common.Address) (contractABI, error) {
+	d.mu.Lock()
+	cached, ok := d.abis[address]
+	d.mu.Unlock()
+
+	if ok {
+		return cached, nil
+	}
+
+	result, err := d.fetchContractABI(ctx, address)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		log.Debug().Err(err).Str("address", address.String()).Msg("contract ABI unavailable")
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	d.abis[address] = result
+	d.mu.Unlock()
+
+	return result, nil
+}
+
+func (d *Decoder) fetchContractABI(ctx context.Context, address common.Address) (contractABI, error) {
+	own, err := d.fetchABI(ctx, address)
+	if errors.Is(err, contracts.ErrNotVerified) {
+		return contractABI{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	impl, err := d.implementationABI(ctx, address)
+	if errors.Is(err, contracts.ErrNotVerified) {
+		return contractABI{own}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if impl != nil {
+		return contractABI{impl, own}, nil
+	}
+
+	return contractABI{own}, nil
+}
+
+// fetchABI fetches and parses a contract's ABI.
+func (d *Decoder) fetchABI(ctx context.Context, address common.Address) (*abi.ABI, error) {
+	raw, err := d.contracts.GetContractABI(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing ABI of %s", address)
+	}
+
+	return &parsed, nil
+}
+
+// implementationABI returns the ABI of a proxy's implementation, or nil if
+// the contract is not a proxy.
+func (d *Decoder) implementationABI(ctx context.Context, address common.Address) (*abi.ABI, error) {
+	info, err := d.contracts.GetContractSourceCode(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(info) == 0 || !info[0].Proxy || info[0].Implementation == nil {
+		return nil, nil
+	}
+
+	impl := *info[0].Implementation
+	if impl == address {
+		return nil, nil
+	}
+
+	return d.fetchABI(ctx, impl)
+}
+
+// Arg is a decoded argument of an event or method call.
+type Arg struct {
+	Name string
+	// Type is the canonical ABI type, e.g. "uint256".
+	Type string
+	// Indexed is set for event arguments stored in topics. Indexed
+	// arguments of dynamic types are decoded to the common.Hash of their
+	// value.
+	Indexed bool
+	// Value is the decoded value as returned by go-ethereum's abi package,
+	// e.g. a *big.Int for a uint256 or a common.Address for an address.
+	Value interface{}
+}
@@ -0,0 +1,399 @@
+package decode_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	"github.com/ryanc414/etherscan-api-go/decode"
+	"github.com/ryanc414/etherscan-api-go/logs"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	weth       = common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc       = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	multicall3 = common.HexToAddress("0xca11bde05977b3631167028862be2a173976ca11")
+	unverified = common.HexToAddress("0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d")
+	sender     = common.HexToAddress("0xe16359506c028e51f16be38986ec5746251e9724")
+	recipient  = common.HexToAddress("0x28c6c06298d514db089934071355e5743bf21d60")
+
+	// recordStore is a verified contract with a bytes[] method that is not
+	// a multicall.
+	recordStore = common.HexToAddress("0x6666666666666666666666666666666666666666")
+
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// countingTransport counts the requests made to Etherscan.
+type countingTransport struct {
+	count int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *countingTransport) requests() int64 {
+	return atomic.LoadInt64(&t.count)
+}
+
+func newDecoder(t *testing.T) (*decode.Decoder, *countingTransport) {
+	m := testbed.NewMockServer("decode", false)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	transport := new(countingTransport)
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+		HTTP:    &http.Client{Transport: transport},
+	})
+
+	return decode.NewDecoder(&client.Contracts), transport
+}
+
+func addressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+func uint256Word(value int64) []byte {
+	return common.LeftPadBytes(big.NewInt(value).Bytes(), 32)
+}
+
+func TestDecodeLog(t *testing.T) {
+	d, _ := newDecoder(t)
+	ctx := context.Background()
+
+	t.Run("Verified", func(t *testing.T) {
+		event, err := d.DecodeLogResponse(ctx, &logs.LogResponse{
+			Address: weth,
+			Topics:  []common.Hash{transferTopic, addressTopic(sender), addressTopic(recipient)},
+			Data:    uint256Word(1000),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &decode.Event{
+			Address:   weth,
+			Name:      "Transfer",
+			Signature: "Transfer(address,address,uint256)",
+			Args: []decode.Arg{
+				{Name: "src", Type: "address", Indexed: true, Value: sender},
+				{Name: "dst", Type: "address", Indexed: true, Value: recipient},
+				{Name: "wad", Type: "uint256", Value: big.NewInt(1000)},
+			},
+		}, event)
+	})
+
+	t.Run("ProxyImplementation", func(t *testing.T) {
+		event, err := d.DecodeLog(
+			ctx,
+			usdc,
+			[]common.Hash{
+				crypto.Keccak256Hash([]byte("Mint(address,address,uint256)")),
+				addressTopic(sender),
+				addressTopic(recipient),
+			},
+			uint256Word(5000000),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Mint", event.Name)
+		assert.False(t, event.Fallback)
+		require.Len(t, event.Args, 3)
+		assert.Equal(t, "minter", event.Args[0].Name)
+		assert.Equal(t, big.NewInt(5000000), event.Args[2].Value)
+	})
+
+	t.Run("ProxyOwn", func(t *testing.T) {
+		impl := common.HexToAddress("0x43506849d7c04f9138d1a2050bbf3a0c054402dd")
+		event, err := d.DecodeLog(
+			ctx,
+			usdc,
+			[]common.Hash{crypto.Keccak256Hash([]byte("Upgraded(address)"))},
+			common.LeftPadBytes(impl.Bytes(), 32),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Upgraded", event.Name)
+		assert.False(t, event.Fallback)
+		assert.Equal(t, []decode.Arg{{Name: "implementation", Type: "address", Value: impl}}, event.Args)
+	})
+
+	t.Run("FallbackERC20", func(t *testing.T) {
+		event, err := d.DecodeLog(
+			ctx,
+			unverified,
+			[]common.Hash{transferTopic, addressTopic(sender), addressTopic(recipient)},
+			uint256Word(1000),
+		)
+		require.NoError(t, err)
+
+		assert.True(t, event.Fallback)
+		assert.Equal(t, "Transfer", event.Name)
+		require.Len(t, event.Args, 3)
+		assert.False(t, event.Args[2].Indexed)
+		assert.Equal(t, big.NewInt(1000), event.Args[2].Value)
+	})
+
+	t.Run("FallbackERC721", func(t *testing.T) {
+		event, err := d.DecodeLog(
+			ctx,
+			unverified,
+			[]common.Hash{
+				transferTopic,
+				addressTopic(sender),
+				addressTopic(recipient),
+				common.BigToHash(big.NewInt(7804)),
+			},
+			nil,
+		)
+		require.NoError(t, err)
+
+		assert.True(t, event.Fallback)
+		assert.Equal(t, "Transfer", event.Name)
+		require.Len(t, event.Args, 3)
+		assert.True(t, event.Args[2].Indexed)
+		assert.Equal(t, big.NewInt(7804), event.Args[2].Value)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := d.DecodeLog(ctx, weth, []common.Hash{crypto.Keccak256Hash([]byte("Unknown()"))}, nil)
+		assert.ErrorIs(t, err, decode.ErrUnknownEvent)
+
+		_, err = d.DecodeLog(ctx, weth, nil, nil)
+		assert.ErrorIs(t, err, decode.ErrUnknownEvent)
+	})
+}
+
+func TestABICache(t *testing.T) {
+	d, transport := newDecoder(t)
+	ctx := context.Background()
+	topics := []common.Hash{transferTopic, addressTopic(sender), addressTopic(recipient)}
+
+	for i := 0; i < 2; i++ {
+		_, err := d.DecodeLog(ctx, usdc, topics, uint256Word(1))
+		require.NoError(t, err)
+	}
+
+	// The proxy's ABI, its source code and the implementation's ABI.
+	assert.Equal(t, int64(3), transport.requests())
+
+	for i := 0; i < 2; i++ {
+		event, err := d.DecodeLog(ctx, unverified, topics, uint256Word(1))
+		require.NoError(t, err)
+		assert.True(t, event.Fallback)
+	}
+
+	// Contracts that are not verified are cached as such.
+	assert.Equal(t, int64(4), transport.requests())
+}
+
+// calls packs the method calls used in tests.
+var calls = mustParseABI(`[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]},
+	{"type":"function","name":"mint","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}]},
+	{"type":"function","name":"multicall","inputs":[{"name":"data","type":"bytes[]"}]},
+	{"type":"function","name":"store","inputs":[{"name":"records","type":"bytes[]"}]},
+	{"type":"function","name":"execute","inputs":[
+		{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"},{"name":"deadline","type":"uint256"}
+	]},
+	{"type":"function","name":"executeBatch","inputs":[
+		{"name":"dest","type":"address[]"},{"name":"value","type":"uint256[]"},{"name":"func","type":"bytes[]"}
+	]},
+	{"type":"function","name":"aggregate3","inputs":[{"name":"calls","type":"tuple[]","components":[
+		{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}
+	]}]}
+]`)
+
+func mustParseABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+func pack(t *testing.T, method string, args ...interface{}) []byte {
+	input, err := calls.Pack(method, args...)
+	require.NoError(t, err)
+
+	return input
+}
+
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+func TestDecodeInput(t *testing.T) {
+	d, _ := newDecoder(t)
+	ctx := context.Background()
+
+	t.Run("Verified", func(t *testing.T) {
+		tx := &accounts.NormalTxInfo{TransactionInfo: accounts.TransactionInfo{
+			To:    weth,
+			Input: pack(t, "transfer", recipient, big.NewInt(1000)),
+		}}
+
+		call, err := d.DecodeNormalTxInput(ctx, tx)
+		require.NoError(t, err)
+
+		assert.Equal(t, &decode.Call{
+			To:        weth,
+			Selector:  [4]byte{0xa9, 0x05, 0x9c, 0xbb},
+			Name:      "transfer",
+			Signature: "transfer(address,uint256)",
+			Args: []decode.Arg{
+				{Name: "dst", Type: "address", Value: recipient},
+				{Name: "wad", Type: "uint256", Value: big.NewInt(1000)},
+			},
+		}, call)
+	})
+
+	t.Run("ProxyImplementation", func(t *testing.T) {
+		tx := &proxy.ProxyTransactionInfo{To: usdc, Input: pack(t, "mint", recipient, big.NewInt(5000000))}
+
+		call, err := d.DecodeProxyTxInput(ctx, tx)
+		require.NoError(t, err)
+
+		assert.True(t, call.Known())
+		assert.False(t, call.Fallback)
+		assert.Equal(t, "mint", call.Name)
+		require.Len(t, call.Args, 2)
+		assert.Equal(t, "_to", call.Args[0].Name)
+	})
+
+	t.Run("Multicall", func(t *testing.T) {
+		input := pack(t, "aggregate3", []call3{
+			{Target: weth, CallData: pack(t, "transfer", recipient, big.NewInt(1))},
+			{Target: usdc, AllowFailure: true, CallData: pack(t, "mint", recipient, big.NewInt(2))},
+			{Target: unverified, CallData: []byte{0x12, 0x34, 0x56, 0x78, 0x9a}},
+		})
+
+		call, err := d.DecodeInput(ctx, multicall3, input)
+		require.NoError(t, err)
+
+		assert.Equal(t, "aggregate3", call.Name)
+		require.Len(t, call.Calls, 3)
+
+		assert.Equal(t, weth, call.Calls[0].To)
+		assert.Equal(t, "transfer", call.Calls[0].Name)
+		assert.Equal(t, big.NewInt(1), call.Calls[0].Args[1].Value)
+
+		assert.Equal(t, usdc, call.Calls[1].To)
+		assert.Equal(t, "mint", call.Calls[1].Name)
+
+		assert.Equal(t, &decode.Call{
+			To:       unverified,
+			Selector: [4]byte{0x12, 0x34, 0x56, 0x78},
+			Data:     []byte{0x9a},
+		}, call.Calls[2])
+	})
+
+	t.Run("FallbackMulticall", func(t *testing.T) {
+		input := pack(t, "multicall", [][]byte{
+			pack(t, "transfer", recipient, big.NewInt(3)),
+		})
+
+		call, err := d.DecodeInput(ctx, unverified, input)
+		require.NoError(t, err)
+
+		assert.True(t, call.Fallback)
+		assert.Equal(t, "multicall(bytes[])", call.Signature)
+		require.Len(t, call.Calls, 1)
+
+		nested := call.Calls[0]
+		assert.Equal(t, unverified, nested.To)
+		assert.True(t, nested.Fallback)
+		assert.Equal(t, []decode.Arg{
+			{Name: "to", Type: "address", Value: recipient},
+			{Name: "value", Type: "uint256", Value: big.NewInt(3)},
+		}, nested.Args)
+	})
+
+	t.Run("BytesArrayNotMulticall", func(t *testing.T) {
+		// Only methods known to batch calls have their bytes[] arguments
+		// decoded as nested calls.
+		records := [][]byte{pack(t, "transfer", recipient, big.NewInt(4))}
+		input := pack(t, "store", records)
+
+		call, err := d.DecodeInput(ctx, recordStore, input)
+		require.NoError(t, err)
+
+		assert.Equal(t, "store", call.Name)
+		assert.Empty(t, call.Calls)
+		assert.Equal(t, records, call.Args[0].Value)
+	})
+
+	t.Run("ExecuteBatch", func(t *testing.T) {
+		// The targets of the calls are in a parallel address[].
+		input := pack(t, "executeBatch",
+			[]common.Address{weth, usdc},
+			[]*big.Int{big.NewInt(0), big.NewInt(0)},
+			[][]byte{
+				pack(t, "transfer", recipient, big.NewInt(5)),
+				pack(t, "mint", recipient, big.NewInt(6)),
+			},
+		)
+
+		call, err := d.DecodeInput(ctx, unverified, input)
+		require.NoError(t, err)
+
+		assert.True(t, call.Fallback)
+		require.Len(t, call.Calls, 2)
+
+		assert.Equal(t, weth, call.Calls[0].To)
+		assert.Equal(t, "transfer", call.Calls[0].Name)
+		assert.Equal(t, usdc, call.Calls[1].To)
+		assert.Equal(t, "mint", call.Calls[1].Name)
+	})
+
+	t.Run("UniversalRouterExecute", func(t *testing.T) {
+		// The inputs of Universal Router commands are not calls, although
+		// they are passed as a bytes[] to a method named execute.
+		inputs := [][]byte{pack(t, "transfer", recipient, big.NewInt(7))}
+		input := pack(t, "execute", []byte{0x0b, 0x00}, inputs, big.NewInt(1700000000))
+
+		call, err := d.DecodeInput(ctx, unverified, input)
+		require.NoError(t, err)
+
+		assert.True(t, call.Fallback)
+		assert.Equal(t, "execute(bytes,bytes[],uint256)", call.Signature)
+		assert.Empty(t, call.Calls)
+		assert.Equal(t, inputs, call.Args[1].Value)
+	})
+
+	t.Run("SelectorOnly", func(t *testing.T) {
+		call, err := d.DecodeInput(ctx, weth, []byte{0xde, 0xad, 0xbe, 0xef, 0x01})
+		require.NoError(t, err)
+
+		assert.False(t, call.Known())
+		assert.Equal(t, [4]byte{0xde, 0xad, 0xbe, 0xef}, call.Selector)
+		assert.Equal(t, []byte{0x01}, call.Data)
+	})
+
+	t.Run("NoInput", func(t *testing.T) {
+		_, err := d.DecodeInput(ctx, weth, nil)
+		assert.ErrorIs(t, err, decode.ErrNoInput)
+
+		_, err = d.DecodeProxyTxInput(ctx, &proxy.ProxyTransactionInfo{Input: []byte{0x60, 0x80}})
+		assert.ErrorIs(t, err, decode.ErrContractCreation)
+	})
+}
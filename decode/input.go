@@ -0,0 +1,269 @@
+package decode
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+var (
+	// ErrNoInput is returned when decoding the input of a transaction that
+	// does not call a method, such as a plain ether transfer.
+	ErrNoInput = errors.New("transaction has no method call input")
+
+	// ErrContractCreation is returned when decoding the input of a contract
+	// creation transaction, which holds the contract's init code.
+	ErrContractCreation = errors.New("contract creation input cannot be decoded")
+)
+
+// maxCallDepth limits how deeply nested calls are decoded.
+const maxCallDepth = 4
+
+// batchTargets describes where the targets of the calls batched by a
+// multicall-style method come from.
+type batchTargets int
+
+const (
+	// targetsSelf batches calls to the contract itself as a bytes[].
+	targetsSelf batchTargets = iota
+	// targetsTuples batches calls as an array of tuples, each holding the
+	// target and input of a call.
+	targetsTuples
+	// targetsParallel batches calls as a bytes[], with their targets at the
+	// same index of an address[].
+	targetsParallel
+)
+
+// multicallMethods are the signatures of methods known to batch calls, whose
+// arguments are decoded as nested calls. Methods are matched by signature
+// rather than name, as other methods of the same name may take bytes[]
+// arguments that hold arbitrary data, such as the commands' inputs of
+// Uniswap's Universal Router execute(bytes,bytes[],uint256).
+var multicallMethods = map[string]batchTargets{
+	// Uniswap V3 periphery and SwapRouter02.
+	"multicall(bytes[])":         targetsSelf,
+	"multicall(uint256,bytes[])": targetsSelf,
+	"multicall(bytes32,bytes[])": targetsSelf,
+
+	// Multicall, Multicall2 and Multicall3.
+	"aggregate((address,bytes)[])":                    targetsTuples,
+	"tryAggregate(bool,(address,bytes)[])":            targetsTuples,
+	"blockAndAggregate((address,bytes)[])":            targetsTuples,
+	"tryBlockAndAggregate(bool,(address,bytes)[])":    targetsTuples,
+	"aggregate3((address,bool,bytes)[])":              targetsTuples,
+	"aggregate3Value((address,bool,uint256,bytes)[])": targetsTuples,
+
+	// ERC-4337 smart accounts.
+	"executeBatch((address,uint256,bytes)[])":   targetsTuples,
+	"executeBatch(address[],bytes[])":           targetsParallel,
+	"executeBatch(address[],uint256[],bytes[])": targetsParallel,
+}
+
+var (
+	addressType = reflect.TypeOf(common.Address{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+)
+
+// Call is a decoded method call. If the method is unknown, only the To,
+// Selector and Data fields are set.
+type Call struct {
+	To       common.Address
+	Selector [4]byte
+	// Name is the name of the method, or empty if it is unknown.
+	Name string
+	// Signature is the canonical method signature, e.g.
+	// "transfer(address,uint256)".
+	Signature string
+	// Args are the method arguments in declaration order.
+	Args []Arg
+	// Calls are the calls nested in a multicall-style batch, such as
+	// Multicall3's aggregate3 or a router's multicall.
+	Calls []*Call
+	// Fallback is set if the call was decoded with the bundled signature
+	// table, as the contract's ABI is unavailable or lacks the method.
+	Fallback bool
+	// Data is the undecoded argument data following the selector. It is
+	// only set if the method is unknown.
+	Data []byte
+}
+
+// Known returns whether the method was identified.
+func (c *Call) Known() bool {
+	return c.Name != ""
+}
+
+// DecodeInput decodes the input of a call to a contract.
+func (d *Decoder) DecodeInput(ctx context.Context, to common.Address, input []byte) (*Call, error) {
+	if len(input) == 0 {
+		return nil, ErrNoInput
+	}
+
+	return d.decodeCall(ctx, to, input, 0)
+}
+
+// DecodeNormalTxInput decodes the input of a transaction returned by the
+// accounts module.
+func (d *Decoder) DecodeNormalTxInput(ctx context.Context, tx *accounts.NormalTxInfo) (*Call, error) {
+	if tx.To == (common.Address{}) {
+		return nil, ErrContractCreation
+	}
+
+	return d.DecodeInput(ctx, tx.To, tx.Input)
+}
+
+// DecodeProxyTxInput decodes the input of a transaction returned by the proxy
+// module.
+func (d *Decoder) DecodeProxyTxInput(ctx context.Context, tx *proxy.ProxyTransactionInfo) (*Call, error) {
+	if tx.To == (common.Address{}) {
+		return nil, ErrContractCreation
+	}
+
+	return d.DecodeInput(ctx, tx.To, tx.Input)
+}
+
+func (d *Decoder) decodeCall(ctx context.Context, to common.Address, input []byte, depth int) (*Call, error) {
+	call := &Call{To: to}
+	if len(input) < len(call.Selector) {
+		call.Data = input
+		return call, nil
+	}
+
+	copy(call.Selector[:], input)
+	data := input[len(call.Selector):]
+
+	abis, err := d.contractABI(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := false
+	for _, contractABI := range abis {
+		method, err := contractABI.MethodById(input)
+		if err != nil {
+			continue
+		}
+
+		decoded = call.decodeArgs(method, data) == nil
+		break
+	}
+
+	if !decoded {
+		if method, ok := signatures.methods[call.Selector]; ok {
+			decoded = call.decodeArgs(&method, data) == nil
+			call.Fallback = decoded
+		}
+	}
+
+	if !decoded {
+		call.Data = data
+		return call, nil
+	}
+
+	if targets, ok := multicallMethods[call.Signature]; ok && depth+1 < maxCallDepth {
+		if err := d.decodeNestedCalls(ctx, call, targets, depth+1); err != nil {
+			return nil, err
+		}
+	}
+
+	return call, nil
+}
+
+func (c *Call) decodeArgs(method *abi.Method, data []byte) error {
+	values, err := method.Inputs.UnpackValues(data)
+	if err != nil {
+		return errors.Wrapf(err, "while unpacking input of method %s", method.Sig)
+	}
+
+	c.Name = method.RawName
+	c.Signature = method.Sig
+	c.Args = make([]Arg, len(method.Inputs))
+
+	for i, input := range method.Inputs {
+		c.Args[i] = Arg{Name: input.Name, Type: input.Type.String(), Value: values[i]}
+	}
+
+	return nil
+}
+
+// decodeNestedCalls decodes the calls batched by a multicall-style method,
+// taking their targets from where multicallMethods says they come from.
+func (d *Decoder) decodeNestedCalls(ctx context.Context, call *Call, targets batchTargets, depth int) error {
+	var addresses []common.Address
+	if targets == targetsParallel {
+		for _, arg := range call.Args {
+			if value, ok := arg.Value.([]common.Address); ok {
+				addresses = value
+				break
+			}
+		}
+	}
+
+	for _, arg := range call.Args {
+		value := reflect.ValueOf(arg.Value)
+		if value.Kind() != reflect.Slice || value.Type().Elem() == reflect.TypeOf(byte(0)) {
+			continue
+		}
+
+		if targets == targetsParallel && value.Len() != len(addresses) {
+			continue
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			to := call.To
+			if targets == targetsParallel {
+				to = addresses[i]
+			}
+
+			target, input, ok := nestedCall(to, value.Index(i))
+			if !ok {
+				break
+			}
+
+			nested, err := d.decodeCall(ctx, target, input, depth)
+			if err != nil {
+				return err
+			}
+
+			call.Calls = append(call.Calls, nested)
+		}
+	}
+
+	return nil
+}
+
+// nestedCall extracts the target and input of a call batched by a
+// multicall-style method.
+func nestedCall(to common.Address, elem reflect.Value) (common.Address, []byte, bool) {
+	if elem.Type() == bytesType {
+		return to, elem.Bytes(), true
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return common.Address{}, nil, false
+	}
+
+	var (
+		target              common.Address
+		input               []byte
+		hasTarget, hasInput bool
+	)
+
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+
+		switch {
+		case field.Type() == addressType && !hasTarget:
+			target, hasTarget = field.Interface().(common.Address), true
+
+		case field.Type() == bytesType && !hasInput:
+			input, hasInput = field.Bytes(), true
+		}
+	}
+
+	return target, input, hasTarget && hasInput
+}
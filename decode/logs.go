@@ -0,0 +1,140 @@
+package decode
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/logs"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+// ErrUnknownEvent is returned when a log matches no event in the emitting
+// contract's ABI or in the bundled signature table.
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Event is a decoded event log.
+type Event struct {
+	Address common.Address
+	Name    string
+	// Signature is the canonical event signature, e.g.
+	// "Transfer(address,address,uint256)".
+	Signature string
+	// Args are the event arguments in declaration order.
+	Args []Arg
+	// Fallback is set if the event was decoded with the bundled signature
+	// table, as the contract's ABI is unavailable or lacks the event.
+	Fallback bool
+}
+
+// DecodeLog decodes a log emitted by a contract.
+func (d *Decoder) DecodeLog(
+	ctx context.Context, address common.Address, topics []common.Hash, data []byte,
+) (*Event, error) {
+	// Anonymous events have no signature topic to identify them by.
+	if len(topics) == 0 {
+		return nil, ErrUnknownEvent
+	}
+
+	abis, err := d.contractABI(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contractABI := range abis {
+		event, err := contractABI.EventByID(topics[0])
+		if err != nil {
+			continue
+		}
+
+		return decodeEvent(address, event, topics, data, false)
+	}
+
+	for i := range signatures.events[topics[0]] {
+		decoded, err := decodeEvent(address, &signatures.events[topics[0]][i], topics, data, true)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, errors.Wrapf(ErrUnknownEvent, "topic %s", topics[0])
+}
+
+// DecodeLogResponse decodes a log returned by the logs module.
+func (d *Decoder) DecodeLogResponse(ctx context.Context, log *logs.LogResponse) (*Event, error) {
+	return d.DecodeLog(ctx, log.Address, log.Topics, log.Data)
+}
+
+// DecodeProxyTxLog decodes a log of a transaction receipt returned by the
+// proxy module.
+func (d *Decoder) DecodeProxyTxLog(ctx context.Context, log *proxy.ProxyTxLog) (*Event, error) {
+	return d.DecodeLog(ctx, log.Address, log.Topics, log.Data)
+}
+
+func decodeEvent(
+	address common.Address, event *abi.Event, topics []common.Hash, data []byte, fallback bool,
+) (*Event, error) {
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+
+	if len(indexed) != len(topics)-1 {
+		return nil, errors.Errorf(
+			"event %s has %d indexed arguments but the log has %d",
+			event.Sig, len(indexed), len(topics)-1,
+		)
+	}
+
+	values, err := event.Inputs.NonIndexed().UnpackValues(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while unpacking data of event %s", event.Sig)
+	}
+
+	decoded := &Event{
+		Address:   address,
+		Name:      event.RawName,
+		Signature: event.Sig,
+		Args:      make([]Arg, len(event.Inputs)),
+		Fallback:  fallback,
+	}
+
+	topicIndex, valueIndex := 1, 0
+	for i, input := range event.Inputs {
+		arg := Arg{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed}
+
+		if input.Indexed {
+			arg.Value, err = decodeTopic(input, topics[topicIndex])
+			if err != nil {
+				return nil, errors.Wrapf(err, "while decoding topic of event %s", event.Sig)
+			}
+
+			topicIndex++
+		} else {
+			arg.Value = values[valueIndex]
+			valueIndex++
+		}
+
+		decoded.Args[i] = arg
+	}
+
+	return decoded, nil
+}
+
+// decodeTopic decodes an indexed argument. Topics of dynamic types hold the
+// hash of the value, which is returned as is.
+func decodeTopic(input abi.Argument, topic common.Hash) (interface{}, error) {
+	if input.Type.T == abi.TupleTy {
+		return topic, nil
+	}
+
+	values := make(map[string]interface{}, 1)
+	if err := abi.ParseTopicsIntoMap(values, abi.Arguments{input}, []common.Hash{topic}); err != nil {
+		return nil, err
+	}
+
+	return values[input.Name], nil
+}
@@ -0,0 +1,48 @@
+package decode
+
+import (
+	"bytes"
+	_ "embed"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// signaturesJSON is an ABI of common events and methods, used to decode logs
+// and input of contracts whose ABI is unavailable.
+//
+//go:embed signatures.json
+var signaturesJSON []byte
+
+// signatureTable indexes the bundled signatures. Several events may share a
+// topic, such as the ERC-20 and ERC-721 Transfer events, which differ only
+// in which arguments are indexed.
+type signatureTable struct {
+	events  map[common.Hash][]abi.Event
+	methods map[[4]byte]abi.Method
+}
+
+var signatures = mustParseSignatures()
+
+func mustParseSignatures() *signatureTable {
+	parsed, err := abi.JSON(bytes.NewReader(signaturesJSON))
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse bundled signatures"))
+	}
+
+	table := &signatureTable{
+		events:  make(map[common.Hash][]abi.Event),
+		methods: make(map[[4]byte]abi.Method, len(parsed.Methods)),
+	}
+
+	for _, event := range parsed.Events {
+		table.events[event.ID] = append(table.events[event.ID], event)
+	}
+
+	for _, method := range parsed.Methods {
+		table.methods[[4]byte(method.ID)] = method
+	}
+
+	return table
+}
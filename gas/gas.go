@@ -17,17 +17,11 @@ type GasClient struct {
 }
 
 // EstimateConfirmationTime returns the estimated time, in seconds, for a
-// transaction to be confirmed on the blockchain.
+// transaction with a gas price in wei to be confirmed on the blockchain.
 func (c *GasClient) EstimateConfirmationTime(
-	ctx context.Context, gasPriceGwei int64,
+	ctx context.Context, gasPrice *big.Int,
 ) (uint64, error) {
-	// 1 gwei = 10^9 wei
-	gasPriceWei := new(big.Int).Mul(
-		big.NewInt(gasPriceGwei),
-		new(big.Int).Exp(big.NewInt(10), big.NewInt(9), nil),
-	)
-
-	req := struct{ GasPrice *big.Int }{gasPriceWei}
+	req := struct{ GasPrice *big.Int }{gasPrice}
 	var result marshallers.UintStr
 
 	err := c.API.Call(ctx, &httpapi.CallParams{
@@ -43,12 +37,12 @@ func (c *GasClient) EstimateConfirmationTime(
 	return result.Unwrap(), nil
 }
 
-// GasPrices describes the current recommended gas prices.
+// GasPrices describes the current recommended gas prices, in gwei.
 type GasPrices struct {
 	LastBlock       uint64            `etherscan:"LastBlock"`
-	SafeGasPrice    uint64            `etherscan:"SafeGasPrice"`
-	ProposeGasPrice uint64            `etherscan:"ProposeGasPrice"`
-	FastGasPrice    uint64            `etherscan:"FastGasPrice"`
+	SafeGasPrice    decimal.Decimal   `etherscan:"SafeGasPrice"`
+	ProposeGasPrice decimal.Decimal   `etherscan:"ProposeGasPrice"`
+	FastGasPrice    decimal.Decimal   `etherscan:"FastGasPrice"`
 	SuggestBaseFee  decimal.Decimal   `etherscan:"suggestBaseFee"`
 	GasUsedRatio    []decimal.Decimal `etherscan:"gasUsedRatio,sep"`
 }
@@ -68,6 +62,68 @@ func (c *GasClient) GetGasOracle(ctx context.Context) (*GasPrices, error) {
 	return result, nil
 }
 
+// FeeRecommendation contains EIP-1559 fee parameters, in wei.
+type FeeRecommendation struct {
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// FeeRecommendations contains EIP-1559 fee parameters for each of the gas
+// oracle's price tiers.
+type FeeRecommendations struct {
+	LastBlock uint64
+	BaseFee   *big.Int
+	Safe      FeeRecommendation
+	Propose   FeeRecommendation
+	Fast      FeeRecommendation
+}
+
+// FeeRecommendations derives EIP-1559 fee parameters from the gas prices.
+// The priority fee of each tier is its gas price less the suggested base fee,
+// and the max fee allows for the base fee doubling before inclusion.
+func (p *GasPrices) FeeRecommendations() *FeeRecommendations {
+	baseFee := gweiToWei(p.SuggestBaseFee)
+
+	return &FeeRecommendations{
+		LastBlock: p.LastBlock,
+		BaseFee:   baseFee,
+		Safe:      recommendFee(baseFee, gweiToWei(p.SafeGasPrice)),
+		Propose:   recommendFee(baseFee, gweiToWei(p.ProposeGasPrice)),
+		Fast:      recommendFee(baseFee, gweiToWei(p.FastGasPrice)),
+	}
+}
+
+func recommendFee(baseFee, gasPrice *big.Int) FeeRecommendation {
+	priorityFee := new(big.Int).Sub(gasPrice, baseFee)
+	if priorityFee.Sign() < 0 {
+		priorityFee.SetInt64(0)
+	}
+
+	maxFee := new(big.Int).Lsh(baseFee, 1)
+	maxFee.Add(maxFee, priorityFee)
+
+	return FeeRecommendation{
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: priorityFee,
+	}
+}
+
+// gweiToWei converts a gwei amount to wei, truncating any fraction of a wei.
+func gweiToWei(gwei decimal.Decimal) *big.Int {
+	return gwei.Shift(9).BigInt()
+}
+
+// GetFeeRecommendations returns EIP-1559 fee parameters derived from the
+// current gas oracle prices.
+func (c *GasClient) GetFeeRecommendations(ctx context.Context) (*FeeRecommendations, error) {
+	prices, err := c.GetGasOracle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return prices.FeeRecommendations(), nil
+}
+
 // AvgGasLimit describes the average gas limit on a particular day.
 type AvgGasLimit struct {
 	Timestamp time.Time `etherscan:"unixTimeStamp"`
@@ -2,6 +2,7 @@ package gas_test
 
 import (
 	"context"
+	"math/big"
 	"testing"
 	"time"
 
@@ -28,7 +29,7 @@ func TestGas(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("EstimateConfirmationTime", func(t *testing.T) {
-		confTime, err := client.Gas.EstimateConfirmationTime(ctx, 20)
+		confTime, err := client.Gas.EstimateConfirmationTime(ctx, big.NewInt(20000000000))
 		require.NoError(t, err)
 		assert.Equal(t, uint64(9227), confTime)
 	})
@@ -39,6 +40,19 @@ func TestGas(t *testing.T) {
 		cupaloy.SnapshotT(t, gas)
 	})
 
+	t.Run("GetFeeRecommendations", func(t *testing.T) {
+		fees, err := client.Gas.GetFeeRecommendations(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, "19230609716", fees.BaseFee.String())
+		assert.Equal(t, "269390284", fees.Safe.MaxPriorityFeePerGas.String())
+		assert.Equal(t, "38730609716", fees.Safe.MaxFeePerGas.String())
+		assert.Equal(t, "1519390284", fees.Propose.MaxPriorityFeePerGas.String())
+		assert.Equal(t, "39980609716", fees.Propose.MaxFeePerGas.String())
+		assert.Equal(t, "4769390284", fees.Fast.MaxPriorityFeePerGas.String())
+		assert.Equal(t, "43230609716", fees.Fast.MaxFeePerGas.String())
+	})
+
 	dateRange := ecommon.DateRange{
 		StartDate: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
 		EndDate:   time.Date(2019, 2, 28, 0, 0, 0, 0, time.UTC),
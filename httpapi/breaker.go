@@ -0,0 +1,259 @@
+//go:generate go-enum -f=$GOFILE
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CircuitState is an enumeration of circuit breaker states.
+// ENUM(closed,open,half-open)
+type CircuitState int32
+
+// ErrCircuitOpen is matched by errors.Is for any CircuitOpenError.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned, before any request is made, while the
+// circuit breaker is open or a half-open probe is in flight.
+type CircuitOpenError struct {
+	// RetryAfter is the time remaining until the circuit half-opens. While a
+	// probe is in flight, it is the open duration, which is how long the
+	// circuit stays open if the probe fails.
+	RetryAfter time.Duration
+}
+
+func (err *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrCircuitOpen, err.RetryAfter)
+}
+
+// Is allows the error to be matched against ErrCircuitOpen.
+func (err *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerParams configures the circuit breaker. Only transport
+// errors, including timeouts, and HTTP 5xx responses count as failures;
+// Etherscan API errors show the service is up and count as successes.
+type CircuitBreakerParams struct {
+	// ErrorRate is the fraction of failed requests, between 0 and 1, at
+	// which the circuit opens.
+	ErrorRate float64
+
+	// MinRequests is the number of requests that must be made within the
+	// current window before the error rate is considered.
+	MinRequests int
+
+	// Window is the length of the fixed window over which the error rate is
+	// measured. Defaults to one minute.
+	Window time.Duration
+
+	// OpenDuration is how long the circuit stays open before half-opening
+	// to let a single probe request through. Defaults to 30 seconds.
+	OpenDuration time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+const (
+	defaultBreakerWindow       = time.Minute
+	defaultBreakerOpenDuration = 30 * time.Second
+)
+
+type circuitBreaker struct {
+	errorRate    float64
+	minRequests  int
+	window       time.Duration
+	openDuration time.Duration
+	now          func() time.Time
+	observer     Observer
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+	changes     []stateChange
+}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+func newCircuitBreaker(params *CircuitBreakerParams, observer Observer) *circuitBreaker {
+	b := &circuitBreaker{
+		errorRate:    params.ErrorRate,
+		minRequests:  params.MinRequests,
+		window:       params.Window,
+		openDuration: params.OpenDuration,
+		now:          params.Now,
+		observer:     observer,
+	}
+
+	if b.window == 0 {
+		b.window = defaultBreakerWindow
+	}
+
+	if b.openDuration == 0 {
+		b.openDuration = defaultBreakerOpenDuration
+	}
+
+	if b.now == nil {
+		b.now = time.Now
+	}
+
+	return b
+}
+
+func (b *circuitBreaker) getState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow returns an error if a request must not be made in the current state.
+// Every allowed request must be followed by a call to record or release.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	switch b.state {
+	case CircuitStateOpen:
+		remaining := b.openDuration - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return &CircuitOpenError{RetryAfter: remaining}
+		}
+
+		b.setState(CircuitStateHalfOpen)
+		b.probing = true
+		return nil
+
+	case CircuitStateHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{RetryAfter: b.openDuration}
+		}
+
+		b.probing = true
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a request that was allowed.
+// A request cancelled by the caller says nothing about Etherscan's health,
+// so it is released instead: a cancelled probe leaves the circuit half-open.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if isCancelled(ctx, err) {
+		b.release()
+		return
+	}
+
+	failed := isOutage(ctx, err)
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.state == CircuitStateHalfOpen {
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.setState(CircuitStateClosed)
+			b.resetWindow()
+		}
+
+		return
+	}
+
+	if b.now().Sub(b.windowStart) >= b.window {
+		b.resetWindow()
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+
+	if b.state == CircuitStateClosed &&
+		b.requests >= b.minRequests &&
+		float64(b.failures) >= b.errorRate*float64(b.requests) &&
+		b.failures > 0 {
+		b.open()
+	}
+}
+
+// release is called instead of record when an allowed request was not made.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) open() {
+	b.openedAt = b.now()
+	b.setState(CircuitStateOpen)
+}
+
+func (b *circuitBreaker) resetWindow() {
+	b.windowStart = b.now()
+	b.requests = 0
+	b.failures = 0
+}
+
+// setState changes the state. The observer is notified once b.mu is
+// released. The caller must hold b.mu.
+func (b *circuitBreaker) setState(state CircuitState) {
+	if state == b.state {
+		return
+	}
+
+	b.changes = append(b.changes, stateChange{from: b.state, to: state})
+	b.state = state
+}
+
+// unlock releases b.mu and then notifies the observer of any state changes,
+// so that the observer may safely call back into the client.
+func (b *circuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+
+	for _, change := range changes {
+		b.observer.CircuitStateChanged(change.from, change.to)
+	}
+}
+
+// isCancelled returns whether a request failed because the caller cancelled
+// its context.
+func isCancelled(ctx context.Context, err error) bool {
+	return err != nil && errors.Is(err, context.Canceled) && ctx.Err() == context.Canceled
+}
+
+// isOutage returns whether an error indicates that Etherscan is unavailable.
+func isOutage(ctx context.Context, err error) bool {
+	if err == nil || isCancelled(ctx, err) {
+		return false
+	}
+
+	switch err := errors.Cause(err).(type) {
+	case *httpError:
+		return err.statusCode >= 500
+
+	case *url.Error:
+		return true
+
+	default:
+		return false
+	}
+}
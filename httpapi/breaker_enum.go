@@ -0,0 +1,50 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package httpapi
+
+import (
+	"fmt"
+)
+
+const (
+	// CircuitStateClosed is a CircuitState of type Closed.
+	CircuitStateClosed CircuitState = iota
+	// CircuitStateOpen is a CircuitState of type Open.
+	CircuitStateOpen
+	// CircuitStateHalfOpen is a CircuitState of type Half-Open.
+	CircuitStateHalfOpen
+)
+
+const _CircuitStateName = "closedopenhalf-open"
+
+var _CircuitStateMap = map[CircuitState]string{
+	0: _CircuitStateName[0:6],
+	1: _CircuitStateName[6:10],
+	2: _CircuitStateName[10:19],
+}
+
+// String implements the Stringer interface.
+func (x CircuitState) String() string {
+	if str, ok := _CircuitStateMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("CircuitState(%d)", x)
+}
+
+var _CircuitStateValue = map[string]CircuitState{
+	_CircuitStateName[0:6]:   0,
+	_CircuitStateName[6:10]:  1,
+	_CircuitStateName[10:19]: 2,
+}
+
+// ParseCircuitState attempts to convert a string to a CircuitState
+func ParseCircuitState(name string) (CircuitState, error) {
+	if x, ok := _CircuitStateValue[name]; ok {
+		return x, nil
+	}
+	return CircuitState(0), fmt.Errorf("%s is not a valid CircuitState", name)
+}
@@ -0,0 +1,323 @@
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const budgetDayFormat = "2006-01-02"
+
+// ErrBudgetExhausted is matched by errors.Is for any BudgetExhaustedError.
+var ErrBudgetExhausted = errors.New("daily call budget exhausted")
+
+// BudgetExhaustedError is returned, before any request is made, when the
+// daily call budget for an API key has been used up and the Budget is
+// configured to fail fast.
+type BudgetExhaustedError struct {
+	Day   string
+	Used  uint64
+	Limit uint64
+}
+
+func (err *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("%s: %d of %d calls used on %s", ErrBudgetExhausted, err.Used, err.Limit, err.Day)
+}
+
+// Is allows the error to be matched against ErrBudgetExhausted.
+func (err *BudgetExhaustedError) Is(target error) bool {
+	return target == ErrBudgetExhausted
+}
+
+// BudgetWarning describes a warning threshold being crossed by an API key.
+type BudgetWarning struct {
+	Day       string
+	Used      uint64
+	Limit     uint64
+	Threshold float64
+}
+
+// BudgetStore persists daily call counts so that they survive restarts.
+type BudgetStore interface {
+	// Load returns the stored count for an API key on a UTC day, formatted
+	// as YYYY-MM-DD. A missing entry is reported as zero.
+	Load(key, day string) (uint64, error)
+
+	// Save stores the count for an API key on a UTC day.
+	Save(key, day string, count uint64) error
+}
+
+// BudgetParams are construction parameters for a Budget.
+type BudgetParams struct {
+	// DailyLimit is the number of calls allowed per API key per UTC day.
+	DailyLimit uint64
+
+	// WarnAt contains fractions of DailyLimit (e.g. 0.8 for 80%) at which
+	// warnings are emitted. Each threshold is reported at most once per key
+	// per day.
+	WarnAt []float64
+
+	// FailFast makes calls fail with a BudgetExhaustedError once the limit
+	// is reached, instead of being sent and counted past the limit.
+	FailFast bool
+
+	// Store optionally persists counts. Counts are only held in memory if nil.
+	Store BudgetStore
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Budget counts calls made per API key per UTC day.
+type Budget struct {
+	limit    uint64
+	warnAt   []float64
+	failFast bool
+	store    BudgetStore
+	now      func() time.Time
+
+	mu     sync.Mutex
+	day    string
+	counts map[string]*budgetCount
+}
+
+type budgetCount struct {
+	used   uint64
+	warned int
+}
+
+// NewBudget constructs a new Budget.
+func NewBudget(params *BudgetParams) *Budget {
+	warnAt := make([]float64, len(params.WarnAt))
+	copy(warnAt, params.WarnAt)
+	sort.Float64s(warnAt)
+
+	now := params.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Budget{
+		limit:    params.DailyLimit,
+		warnAt:   warnAt,
+		failFast: params.FailFast,
+		store:    params.Store,
+		now:      now,
+		counts:   make(map[string]*budgetCount),
+	}
+}
+
+// Used returns the number of calls made with an API key on the current UTC day.
+func (b *Budget) Used(key string) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.getCount(key).used
+}
+
+// Remaining returns the number of calls left for an API key on the current
+// UTC day.
+func (b *Budget) Remaining(key string) uint64 {
+	used := b.Used(key)
+	if used >= b.limit {
+		return 0
+	}
+
+	return b.limit - used
+}
+
+// reserve counts a call for an API key. It returns a warning if the call
+// crossed a warning threshold, or an error if the budget is exhausted and
+// the call must not be made.
+func (b *Budget) reserve(key string) (*BudgetWarning, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.getCount(key)
+	if b.failFast && count.used >= b.limit {
+		return nil, &BudgetExhaustedError{
+			Day:   b.day,
+			Used:  count.used,
+			Limit: b.limit,
+		}
+	}
+
+	count.used++
+
+	if b.store != nil {
+		if err := b.store.Save(key, b.day, count.used); err != nil {
+			log.Error().Err(err).Msg("failed to save call budget")
+		}
+	}
+
+	return b.checkThresholds(count), nil
+}
+
+func (b *Budget) checkThresholds(count *budgetCount) *BudgetWarning {
+	var warning *BudgetWarning
+
+	for count.warned < len(b.warnAt) {
+		threshold := b.warnAt[count.warned]
+		if float64(count.used) < threshold*float64(b.limit) {
+			break
+		}
+
+		warning = &BudgetWarning{
+			Day:       b.day,
+			Used:      count.used,
+			Limit:     b.limit,
+			Threshold: threshold,
+		}
+		count.warned++
+	}
+
+	return warning
+}
+
+// getCount returns the count for a key on the current day, resetting all
+// counts when the UTC day rolls over. The caller must hold b.mu.
+func (b *Budget) getCount(key string) *budgetCount {
+	day := b.now().UTC().Format(budgetDayFormat)
+	if day != b.day {
+		b.day = day
+		b.counts = make(map[string]*budgetCount)
+	}
+
+	if count, ok := b.counts[key]; ok {
+		return count
+	}
+
+	count := new(budgetCount)
+	if b.store != nil {
+		used, err := b.store.Load(key, day)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to load call budget")
+		}
+
+		count.used = used
+		b.skipPassedThresholds(count)
+	}
+
+	b.counts[key] = count
+	return count
+}
+
+// skipPassedThresholds avoids repeating warnings that were already emitted
+// before a restart.
+func (b *Budget) skipPassedThresholds(count *budgetCount) {
+	for count.warned < len(b.warnAt) &&
+		float64(count.used) >= b.warnAt[count.warned]*float64(b.limit) {
+		count.warned++
+	}
+}
+
+// FileBudgetStore is a BudgetStore backed by a JSON file. API keys are
+// stored as SHA-256 fingerprints rather than in plain text, and only the
+// most recent day is retained.
+type FileBudgetStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileBudgetStore constructs a FileBudgetStore that reads and writes the
+// file at path.
+func NewFileBudgetStore(path string) *FileBudgetStore {
+	return &FileBudgetStore{path: path}
+}
+
+type budgetFile struct {
+	Day    string            `json:"day"`
+	Counts map[string]uint64 `json:"counts"`
+}
+
+// Load implements BudgetStore.
+func (s *FileBudgetStore) Load(key, day string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	if f.Day != day {
+		return 0, nil
+	}
+
+	return f.Counts[keyFingerprint(key)], nil
+}
+
+// Save implements BudgetStore.
+func (s *FileBudgetStore) Save(key, day string, count uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if f.Day != day {
+		f = &budgetFile{Day: day, Counts: make(map[string]uint64)}
+	}
+	f.Counts[keyFingerprint(key)] = count
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *FileBudgetStore) read() (*budgetFile, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return &budgetFile{Counts: make(map[string]uint64)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := new(budgetFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, errors.Wrapf(err, "while reading budget file %s", s.path)
+	}
+
+	if f.Counts == nil {
+		f.Counts = make(map[string]uint64)
+	}
+
+	return f, nil
+}
+
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
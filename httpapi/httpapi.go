@@ -8,9 +8,13 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/ryanc414/etherscan-api-go/marshallers"
 )
 
@@ -24,29 +28,73 @@ type Params struct {
 	APIKey  string
 	BaseURL *url.URL
 	HTTP    *http.Client
+
+	// ChainID is sent as the chainid parameter with every call if non-zero,
+	// as required by Etherscan's multichain API.
+	ChainID uint64
+
+	// Retry optionally configures retries of failed requests. Requests are
+	// not retried if nil.
+	Retry *RetryPolicy
+
+	// Budget optionally tracks and limits the number of calls made per day.
+	Budget *Budget
+
+	// CircuitBreaker optionally configures a circuit breaker that fails
+	// calls fast while Etherscan is unavailable.
+	CircuitBreaker *CircuitBreakerParams
+
+	// Observer is optionally notified of client events such as budget warnings.
+	Observer Observer
+
+	// Node optionally serves proxy module calls from a JSON-RPC node, such
+	// as a go-ethereum *rpc.Client, according to ProviderPolicy.
+	Node ecommon.Provider
+
+	// ProviderPolicy chooses between Etherscan and Node for proxy module
+	// calls. It is ignored if Node is nil.
+	ProviderPolicy ecommon.ProviderPolicy
 }
 
 type APIClient struct {
-	apiURL url.URL
-	http   *http.Client
+	apiURL   url.URL
+	apiKey   string
+	chainID  uint64
+	retry    *RetryPolicy
+	http     *http.Client
+	budget   *Budget
+	breaker  *circuitBreaker
+	observer Observer
 }
 
 func New(params *Params) *APIClient {
 	apiURL := getBaseURL(params)
 	apiURL.Path = path.Join(apiURL.Path, "api")
 
-	q := apiURL.Query()
-	q.Set("apikey", params.APIKey)
-	apiURL.RawQuery = q.Encode()
-
 	httpClient := params.HTTP
 	if httpClient == nil {
 		httpClient = new(http.Client)
 	}
 
+	observer := params.Observer
+	if observer == nil {
+		observer = NopObserver{}
+	}
+
+	var breaker *circuitBreaker
+	if params.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(params.CircuitBreaker, observer)
+	}
+
 	return &APIClient{
-		apiURL: apiURL,
-		http:   httpClient,
+		apiURL:   apiURL,
+		apiKey:   params.APIKey,
+		chainID:  params.ChainID,
+		retry:    params.Retry,
+		http:     httpClient,
+		budget:   params.Budget,
+		breaker:  breaker,
+		observer: observer,
 	}
 }
 
@@ -95,9 +143,15 @@ type apiResponse struct {
 	Status  string          `json:"status"`
 	Message string          `json:"message"`
 	Result  json.RawMessage `json:"result"`
+
+	// Error is set by proxy module calls that fail, which respond with a
+	// JSON-RPC error object instead of a status.
+	Error *RPCError `json:"error"`
 }
 
 func (r APIClient) Get(ctx context.Context, params *RequestParams) (json.RawMessage, error) {
+	opts := r.getCallOptions(ctx)
+
 	u := r.apiURL
 	q := u.Query()
 	q.Set("module", params.Module)
@@ -107,9 +161,133 @@ func (r APIClient) Get(ctx context.Context, params *RequestParams) (json.RawMess
 		q.Set(k, v)
 	}
 
+	q.Set("apikey", opts.apiKey)
+	if opts.chainID != 0 {
+		q.Set("chainid", strconv.FormatUint(opts.chainID, 10))
+	}
+
 	u.RawQuery = q.Encode()
 
-	bodyData, err := r.makeRequest(ctx, u.String(), http.MethodGet)
+	if opts.meta != nil {
+		if !opts.meta.acquire() {
+			return nil, ErrMetaInUse
+		}
+		defer opts.meta.release()
+	}
+
+	start := time.Now()
+
+	var result json.RawMessage
+	err := r.withRetries(ctx, opts.retry, func() error {
+		if opts.meta != nil {
+			opts.meta.startAttempt()
+			opts.meta.Attempts++
+		}
+
+		var err error
+		result, err = r.attempt(ctx, &u, &opts)
+		return err
+	})
+
+	if opts.meta != nil {
+		opts.meta.Elapsed = time.Since(start)
+	}
+
+	return result, err
+}
+
+func (r APIClient) getCallOptions(ctx context.Context) callOptions {
+	opts := callOptions{
+		apiKey:  r.apiKey,
+		chainID: r.chainID,
+		retry:   r.retry,
+	}
+
+	overrides, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	for _, override := range overrides {
+		override(&opts)
+	}
+
+	return opts
+}
+
+func (r APIClient) withRetries(ctx context.Context, policy *RetryPolicy, f func() error) error {
+	maxAttempts := policy.maxAttempts()
+
+	for i := 0; ; i++ {
+		err := f()
+		if err == nil || i+1 >= maxAttempts || !isRetriable(ctx, err) {
+			return err
+		}
+
+		delay := policy.backoff(i)
+		log.Debug().Err(err).Dur("delay", delay).Msg("retrying failed request")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetriable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && IsTemporary(err)
+}
+
+// IsTemporary returns whether err is a transient failure to reach Etherscan
+// rather than an error about the call itself: a transport error, an HTTP 429
+// or 5xx response or an Etherscan rate-limit error. These are the errors
+// retried under a RetryPolicy.
+func IsTemporary(err error) bool {
+	switch err := errors.Cause(err).(type) {
+	case *httpError:
+		return err.statusCode == http.StatusTooManyRequests || err.statusCode >= 500
+
+	case responseError:
+		return err.isRateLimit()
+
+	case *url.Error:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// CircuitState returns the current state of the circuit breaker. It is
+// always closed if no circuit breaker is configured.
+func (r APIClient) CircuitState() CircuitState {
+	if r.breaker == nil {
+		return CircuitStateClosed
+	}
+
+	return r.breaker.getState()
+}
+
+func (r APIClient) attempt(ctx context.Context, u *url.URL, opts *callOptions) (json.RawMessage, error) {
+	if r.breaker != nil {
+		if err := r.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.reserveBudget(opts.apiKey); err != nil {
+		if r.breaker != nil {
+			r.breaker.release()
+		}
+
+		return nil, err
+	}
+
+	bodyData, err := r.makeRequest(ctx, u, opts)
+	if r.breaker != nil {
+		r.breaker.record(ctx, err)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +297,16 @@ func (r APIClient) Get(ctx context.Context, params *RequestParams) (json.RawMess
 		return nil, err
 	}
 
+	if opts.meta != nil {
+		opts.meta.Status = rspBody.Status
+		opts.meta.Message = rspBody.Message
+		opts.meta.Result = rspBody.Result
+	}
+
+	if rspBody.Error != nil {
+		return nil, rspBody.Error
+	}
+
 	if rspBody.Status != "" && rspBody.Status != rspStatusOK {
 		return nil, newResponseErr(&rspBody)
 	}
@@ -126,14 +314,45 @@ func (r APIClient) Get(ctx context.Context, params *RequestParams) (json.RawMess
 	return rspBody.Result, nil
 }
 
-func (r APIClient) makeRequest(ctx context.Context, urlStr, method string) ([]byte, error) {
-	log.Debug().Str("url", urlStr).Str("method", method).Msg("making HTTP request")
+func (r APIClient) reserveBudget(key string) error {
+	if r.budget == nil {
+		return nil
+	}
+
+	warning, err := r.budget.reserve(key)
+	if err != nil {
+		return err
+	}
+
+	if warning != nil {
+		log.Warn().
+			Uint64("used", warning.Used).
+			Uint64("limit", warning.Limit).
+			Float64("threshold", warning.Threshold).
+			Msg("daily call budget threshold reached")
+		r.observer.BudgetWarning(key, warning)
+	}
+
+	return nil
+}
+
+func (r APIClient) makeRequest(ctx context.Context, u *url.URL, opts *callOptions) ([]byte, error) {
+	urlStr := u.String()
+	log.Debug().Str("url", urlStr).Str("method", http.MethodGet).Msg("making HTTP request")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if opts.bypassCache {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+
+	if opts.debug != nil {
+		*opts.debug = DebugCapture{URL: redactAPIKey(u)}
+	}
+
 	rsp, err := r.http.Do(req)
 	if err != nil {
 		return nil, err
@@ -141,8 +360,22 @@ func (r APIClient) makeRequest(ctx context.Context, urlStr, method string) ([]by
 
 	defer rsp.Body.Close()
 
+	if opts.debug != nil {
+		opts.debug.StatusCode = rsp.StatusCode
+	}
+
+	if opts.meta != nil {
+		opts.meta.StatusCode = rsp.StatusCode
+		opts.meta.Header = rsp.Header
+	}
+
 	if rsp.StatusCode != http.StatusOK {
-		return nil, newHTTPErr(rsp)
+		httpErr := newHTTPErr(rsp)
+		if opts.debug != nil {
+			opts.debug.Body = httpErr.body
+		}
+
+		return nil, httpErr
 	}
 
 	bodyData, err := ioutil.ReadAll(rsp.Body)
@@ -150,12 +383,28 @@ func (r APIClient) makeRequest(ctx context.Context, urlStr, method string) ([]by
 		return nil, err
 	}
 
+	if opts.debug != nil {
+		opts.debug.Body = bodyData
+	}
+
 	return bodyData, nil
 }
 
+func redactAPIKey(u *url.URL) string {
+	redacted := *u
+	q := redacted.Query()
+	if q.Get("apikey") != "" {
+		q.Set("apikey", "REDACTED")
+	}
+	redacted.RawQuery = q.Encode()
+
+	return redacted.String()
+}
+
 type httpError struct {
-	status string
-	body   []byte
+	status     string
+	statusCode int
+	body       []byte
 }
 
 func (err *httpError) Error() string {
@@ -174,8 +423,9 @@ func newHTTPErr(rsp *http.Response) *httpError {
 	}
 
 	return &httpError{
-		status: rsp.Status,
-		body:   body,
+		status:     rsp.Status,
+		statusCode: rsp.StatusCode,
+		body:       body,
 	}
 }
 
@@ -196,6 +446,77 @@ func (err responseError) Error() string {
 	)
 }
 
+// isRateLimit returns whether the error was caused by exceeding the API
+// key's rate limit, e.g. "Max rate limit reached".
+func (err responseError) isRateLimit() bool {
+	var result string
+	if json.Unmarshal(err.rsp.Result, &result) != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(result), "rate limit")
+}
+
+// RPCError is a JSON-RPC error returned by a proxy module call, such as a
+// reverted eth_call. It implements the rpc.Error and rpc.DataError interfaces
+// of go-ethereum, so the code and any revert data can be read in the same way
+// as from errors returned by a node.
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (err *RPCError) Error() string {
+	return err.Message
+}
+
+// ErrorCode returns the JSON-RPC error code.
+func (err *RPCError) ErrorCode() int {
+	return err.Code
+}
+
+// ErrorData returns the error data, which is the hex encoded revert data
+// for reverted calls.
+func (err *RPCError) ErrorData() interface{} {
+	return err.Data
+}
+
+// IsEmptyResult returns whether err is an Etherscan error response stating
+// that a query matched no records, e.g. "No transactions found". This is
+// returned instead of an empty result, including when paging past the last
+// page of results.
+func IsEmptyResult(err error) bool {
+	rspErr, ok := errors.Cause(err).(responseError)
+	if !ok {
+		return false
+	}
+
+	var result []json.RawMessage
+	if len(rspErr.rsp.Result) != 0 && json.Unmarshal(rspErr.rsp.Result, &result) != nil {
+		return false
+	}
+
+	return len(result) == 0 && strings.HasPrefix(rspErr.rsp.Message, "No ")
+}
+
+// ResultMessage returns the message in the result field of an Etherscan error
+// response, e.g. "Contract source code not verified". It returns false if err
+// is not an error response or its result is not a string.
+func ResultMessage(err error) (string, bool) {
+	rspErr, ok := errors.Cause(err).(responseError)
+	if !ok {
+		return "", false
+	}
+
+	var result string
+	if json.Unmarshal(rspErr.rsp.Result, &result) != nil {
+		return "", false
+	}
+
+	return result, true
+}
+
 func newResponseErr(rsp *apiResponse) responseError {
 	return responseError{rsp: *rsp}
 }
@@ -0,0 +1,416 @@
+package httpapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/httpapi"
+	"github.com/ryanc414/etherscan-api-go/marshallers"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testObserver struct {
+	warnings []*httpapi.BudgetWarning
+	states   []httpapi.CircuitState
+}
+
+func (o *testObserver) BudgetWarning(key string, warning *httpapi.BudgetWarning) {
+	o.warnings = append(o.warnings, warning)
+}
+
+func (o *testObserver) CircuitStateChanged(from, to httpapi.CircuitState) {
+	o.states = append(o.states, to)
+}
+
+func TestBudget(t *testing.T) {
+	m := testbed.NewMockServer("stats", true)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	now := time.Date(2021, 10, 1, 23, 0, 0, 0, time.UTC)
+	store := httpapi.NewFileBudgetStore(filepath.Join(t.TempDir(), "budget.json"))
+	params := httpapi.BudgetParams{
+		DailyLimit: 2,
+		WarnAt:     []float64{0.5, 1},
+		FailFast:   true,
+		Store:      store,
+		Now:        func() time.Time { return now },
+	}
+
+	budget := httpapi.NewBudget(&params)
+	observer := new(testObserver)
+	client := httpapi.New(&httpapi.Params{
+		APIKey:   m.APIKey,
+		BaseURL:  u,
+		Budget:   budget,
+		Observer: observer,
+	})
+
+	ctx := context.Background()
+	call := func(client *httpapi.APIClient) error {
+		return client.Call(ctx, &httpapi.CallParams{
+			Module: "stats",
+			Action: "ethsupply",
+			Result: new(marshallers.BigInt),
+		})
+	}
+
+	t.Run("Exhausted", func(t *testing.T) {
+		require.NoError(t, call(client))
+		require.NoError(t, call(client))
+
+		err := call(client)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, httpapi.ErrBudgetExhausted))
+
+		var exhausted *httpapi.BudgetExhaustedError
+		require.True(t, errors.As(err, &exhausted))
+		assert.Equal(t, "2021-10-01", exhausted.Day)
+		assert.Equal(t, uint64(2), exhausted.Used)
+
+		require.Len(t, observer.warnings, 2)
+		assert.Equal(t, 0.5, observer.warnings[0].Threshold)
+		assert.Equal(t, 1.0, observer.warnings[1].Threshold)
+		assert.Equal(t, uint64(0), budget.Remaining(m.APIKey))
+	})
+
+	t.Run("Persisted", func(t *testing.T) {
+		restarted := httpapi.NewBudget(&params)
+		assert.Equal(t, uint64(2), restarted.Used(m.APIKey))
+		assert.Equal(t, uint64(0), restarted.Used("other key"))
+	})
+
+	t.Run("NextDay", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		assert.Equal(t, uint64(0), budget.Used(m.APIKey))
+		require.NoError(t, call(client))
+		assert.Equal(t, uint64(1), budget.Used(m.APIKey))
+	})
+}
+
+func TestCallOptions(t *testing.T) {
+	m := testbed.NewMockServer("stats", true)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	budget := httpapi.NewBudget(&httpapi.BudgetParams{DailyLimit: 100})
+	client := httpapi.New(&httpapi.Params{
+		APIKey:  "default key",
+		BaseURL: u,
+		Budget:  budget,
+	})
+
+	callParams := httpapi.CallParams{
+		Module: "stats",
+		Action: "ethsupply",
+		Result: new(marshallers.BigInt),
+	}
+
+	t.Run("DefaultAPIKey", func(t *testing.T) {
+		err := client.Call(context.Background(), &callParams)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+	})
+
+	t.Run("APIKeyOverride", func(t *testing.T) {
+		var capture httpapi.DebugCapture
+		ctx := httpapi.WithCallOptions(
+			context.Background(),
+			httpapi.WithAPIKey(m.APIKey),
+			httpapi.WithDebugCapture(&capture),
+		)
+
+		err := client.Call(ctx, &callParams)
+		require.NoError(t, err)
+		assert.Equal(t, "116487067186500000000000000", callParams.Result.(*marshallers.BigInt).Unwrap().String())
+
+		assert.Equal(t, http.StatusOK, capture.StatusCode)
+		assert.Contains(t, capture.URL, "apikey=REDACTED")
+		assert.NotContains(t, capture.URL, m.APIKey)
+		assert.Contains(t, string(capture.Body), "116487067186500000000000000")
+
+		assert.Equal(t, uint64(1), budget.Used("default key"))
+		assert.Equal(t, uint64(1), budget.Used(m.APIKey))
+	})
+}
+
+func TestRequestOverrides(t *testing.T) {
+	var (
+		requests []*http.Request
+		failures = 2
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req)
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":"42"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	client := httpapi.New(&httpapi.Params{
+		APIKey:  "key",
+		BaseURL: u,
+		ChainID: 1,
+	})
+
+	var meta httpapi.ResponseMeta
+	ctx := httpapi.WithCallOptions(
+		context.Background(),
+		httpapi.WithChainID(5),
+		httpapi.WithCacheBypass(),
+		httpapi.WithRetryPolicy(&httpapi.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+		httpapi.WithMeta(&meta),
+	)
+
+	result := new(marshallers.BigInt)
+	err = client.Call(ctx, &httpapi.CallParams{
+		Module: "stats",
+		Action: "ethsupply",
+		Result: result,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "42", result.Unwrap().String())
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "5", requests[2].URL.Query().Get("chainid"))
+	assert.Equal(t, "no-cache", requests[2].Header.Get("Cache-Control"))
+
+	assert.Equal(t, 3, meta.Attempts)
+	assert.Equal(t, "1", meta.Status)
+	assert.Equal(t, "OK", meta.Message)
+	assert.Equal(t, `"42"`, string(meta.Result))
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.NotEmpty(t, meta.Header.Get("Content-Type"))
+	assert.Greater(t, meta.Elapsed, time.Duration(0))
+
+	t.Run("NoRetry", func(t *testing.T) {
+		requests = nil
+		failures = 1
+
+		err := client.Call(context.Background(), &httpapi.CallParams{
+			Module: "stats",
+			Action: "ethsupply",
+			Result: result,
+		})
+		require.Error(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "1", requests[0].URL.Query().Get("chainid"))
+		assert.Empty(t, requests[0].Header.Get("Cache-Control"))
+	})
+}
+
+func TestResponseMeta(t *testing.T) {
+	var (
+		requests int
+		received = make(chan struct{})
+		block    = make(chan struct{})
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+
+		switch req.URL.Query().Get("action") {
+		case "blocking":
+			received <- struct{}{}
+			<-block
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":"42"}`))
+
+		default:
+			if requests == 1 {
+				_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`))
+				return
+			}
+
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	client := httpapi.New(&httpapi.Params{APIKey: "key", BaseURL: u})
+
+	var meta httpapi.ResponseMeta
+	ctx := httpapi.WithCallOptions(context.Background(), httpapi.WithMeta(&meta))
+	call := func(action string) error {
+		return client.Call(ctx, &httpapi.CallParams{
+			Module: "stats",
+			Action: action,
+			Result: new(marshallers.BigInt),
+		})
+	}
+
+	t.Run("ResetEachAttempt", func(t *testing.T) {
+		retryCtx := httpapi.WithCallOptions(
+			ctx, httpapi.WithRetryPolicy(&httpapi.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+		)
+
+		err := client.Call(retryCtx, &httpapi.CallParams{
+			Module: "stats",
+			Action: "ethsupply",
+			Result: new(marshallers.BigInt),
+		})
+		require.Error(t, err)
+
+		assert.Equal(t, 2, meta.Attempts)
+		assert.Equal(t, http.StatusBadGateway, meta.StatusCode)
+		assert.Empty(t, meta.Status)
+		assert.Empty(t, meta.Message)
+		assert.Nil(t, meta.Result)
+	})
+
+	t.Run("ConcurrentUse", func(t *testing.T) {
+		done := make(chan error)
+		go func() {
+			done <- call("blocking")
+		}()
+
+		<-received
+		assert.ErrorIs(t, call("ethsupply"), httpapi.ErrMetaInUse)
+
+		close(block)
+		require.NoError(t, <-done)
+		assert.Equal(t, `"42"`, string(meta.Result))
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		requests int
+		down     = true
+		blocking atomic.Bool
+		received = make(chan struct{})
+		block    = make(chan struct{})
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if blocking.Load() {
+			received <- struct{}{}
+			<-block
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		if down {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid address format"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	observer := new(testObserver)
+	client := httpapi.New(&httpapi.Params{
+		APIKey:  "key",
+		BaseURL: u,
+		CircuitBreaker: &httpapi.CircuitBreakerParams{
+			ErrorRate:    0.5,
+			MinRequests:  2,
+			OpenDuration: time.Minute,
+			Now:          func() time.Time { return now },
+		},
+		Observer: observer,
+	})
+
+	ctx := context.Background()
+	call := func() error {
+		return client.Call(ctx, &httpapi.CallParams{
+			Module: "account",
+			Action: "balance",
+			Result: new(marshallers.BigInt),
+		})
+	}
+
+	require.Error(t, call())
+	assert.Equal(t, httpapi.CircuitStateClosed, client.CircuitState())
+	require.Error(t, call())
+	assert.Equal(t, httpapi.CircuitStateOpen, client.CircuitState())
+
+	err = call()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, httpapi.ErrCircuitOpen))
+	assert.Equal(t, 2, requests)
+
+	t.Run("ProbeFails", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		require.Error(t, call())
+		assert.Equal(t, 3, requests)
+		assert.Equal(t, httpapi.CircuitStateOpen, client.CircuitState())
+	})
+
+	t.Run("ProbeCancelled", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		blocking.Store(true)
+
+		probeCtx, cancel := context.WithCancel(ctx)
+		probeErr := make(chan error)
+		go func() {
+			probeErr <- client.Call(probeCtx, &httpapi.CallParams{
+				Module: "account",
+				Action: "balance",
+				Result: new(marshallers.BigInt),
+			})
+		}()
+
+		<-received
+
+		// Other calls are rejected while the probe is in flight.
+		var openErr *httpapi.CircuitOpenError
+		require.ErrorAs(t, call(), &openErr)
+		assert.Equal(t, time.Minute, openErr.RetryAfter)
+
+		cancel()
+		assert.ErrorIs(t, <-probeErr, context.Canceled)
+		blocking.Store(false)
+		close(block)
+
+		assert.Equal(t, 4, requests)
+		assert.Equal(t, httpapi.CircuitStateHalfOpen, client.CircuitState())
+	})
+
+	t.Run("ProbeSucceeds", func(t *testing.T) {
+		down = false
+
+		err := call()
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, httpapi.ErrCircuitOpen))
+		assert.Equal(t, 5, requests)
+		assert.Equal(t, httpapi.CircuitStateClosed, client.CircuitState())
+	})
+
+	assert.Equal(t, []httpapi.CircuitState{
+		httpapi.CircuitStateOpen,
+		httpapi.CircuitStateHalfOpen,
+		httpapi.CircuitStateOpen,
+		httpapi.CircuitStateHalfOpen,
+		httpapi.CircuitStateClosed,
+	}, observer.states)
+}
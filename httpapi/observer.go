@@ -0,0 +1,21 @@
+package httpapi
+
+// Observer is notified of notable events in the API client. Embed
+// NopObserver to implement only the methods of interest.
+type Observer interface {
+	// BudgetWarning is called when an API key crosses one of the configured
+	// warning thresholds of its daily call budget.
+	BudgetWarning(key string, warning *BudgetWarning)
+
+	// CircuitStateChanged is called when the circuit breaker changes state.
+	CircuitStateChanged(from, to CircuitState)
+}
+
+// NopObserver implements Observer and ignores all events.
+type NopObserver struct{}
+
+// BudgetWarning implements Observer.
+func (NopObserver) BudgetWarning(string, *BudgetWarning) {}
+
+// CircuitStateChanged implements Observer.
+func (NopObserver) CircuitStateChanged(CircuitState, CircuitState) {}
@@ -0,0 +1,226 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CallOption overrides the client's behaviour for individual calls. Options
+// are attached to a context with WithCallOptions and apply to every call
+// made with that context, which allows a single client to serve multiple
+// tenants.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	apiKey      string
+	chainID     uint64
+	bypassCache bool
+	retry       *RetryPolicy
+	debug       *DebugCapture
+	meta        *ResponseMeta
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx carrying the given call options, in
+// addition to any options already attached to ctx. Later options take
+// precedence over earlier ones.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+
+	merged := make([]CallOption, 0, len(existing)+len(opts))
+	merged = append(merged, existing...)
+	merged = append(merged, opts...)
+
+	return context.WithValue(ctx, callOptionsKey{}, merged)
+}
+
+// WithAPIKey overrides the API key used for a call. Budgets are tracked
+// against the overriding key.
+func WithAPIKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.apiKey = key
+	}
+}
+
+// WithChainID overrides the chain ID sent with a call.
+func WithChainID(chainID uint64) CallOption {
+	return func(o *callOptions) {
+		o.chainID = chainID
+	}
+}
+
+// WithCacheBypass requests that a call is not served from a cache, by
+// sending a "Cache-Control: no-cache" header. This applies to any caching
+// transport configured on the HTTP client as well as to intermediaries.
+func WithCacheBypass() CallOption {
+	return func(o *callOptions) {
+		o.bypassCache = true
+	}
+}
+
+// WithRetryPolicy overrides the retry policy for a call. A nil policy
+// disables retries.
+func WithRetryPolicy(policy *RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = policy
+	}
+}
+
+// WithDebugCapture records the raw HTTP exchange of a call into capture.
+func WithDebugCapture(capture *DebugCapture) CallOption {
+	return func(o *callOptions) {
+		o.debug = capture
+	}
+}
+
+// WithMeta records metadata about the response to each call made with the
+// context into meta, replacing what was recorded for any earlier call. For
+// methods that make several requests, such as those that page through
+// results, meta describes the last request. A ResponseMeta may only be used
+// by one call at a time: calls made while it is in use by another fail with
+// ErrMetaInUse.
+func WithMeta(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.meta = meta
+	}
+}
+
+// RetryPolicy controls how failed requests are retried. Transport errors,
+// HTTP 429 and 5xx responses and Etherscan rate-limit errors are retried;
+// other errors are returned immediately.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first.
+	// Values below 1 are treated as 1.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It doubles for each
+	// subsequent retry.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between retries, if non-zero.
+	MaxBackoff time.Duration
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.Backoff << uint(retry)
+	if p.MaxBackoff != 0 && (delay > p.MaxBackoff || delay < p.Backoff) {
+		return p.MaxBackoff
+	}
+
+	return delay
+}
+
+// DebugCapture contains the raw HTTP exchange of the final attempt of a call.
+type DebugCapture struct {
+	// URL is the request URL with the API key redacted.
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+// ErrMetaInUse is returned by calls made with a ResponseMeta that is already
+// in use by another call, as the metadata of concurrent calls would be mixed.
+var ErrMetaInUse = errors.New("ResponseMeta is in use by another call")
+
+// ResponseMeta contains metadata about the response to a call, alongside
+// the decoded result. Fields describing the response are reset before each
+// attempt, so describe the final attempt only.
+type ResponseMeta struct {
+	// Status and Message are the status and message fields of the Etherscan
+	// response. They are empty for proxy module calls, which return JSON-RPC
+	// responses.
+	Status  string
+	Message string
+
+	// Result contains the raw result before decoding.
+	Result json.RawMessage
+
+	// StatusCode and Header describe the HTTP response to the final attempt.
+	StatusCode int
+	Header     http.Header
+
+	// Attempts is the number of requests made, including retries.
+	Attempts int
+
+	// Elapsed is the total time taken by the call, including retries.
+	Elapsed time.Duration
+
+	// Node is set if the call was served by a JSON-RPC node instead of
+	// Etherscan, in which case the Etherscan and HTTP fields are empty.
+	Node bool
+
+	inUse int32
+}
+
+// acquire marks meta as in use by a call, returning false if it is already
+// in use, and resets it.
+func (meta *ResponseMeta) acquire() bool {
+	if !atomic.CompareAndSwapInt32(&meta.inUse, 0, 1) {
+		return false
+	}
+
+	meta.Attempts = 0
+	meta.Elapsed = 0
+	meta.Node = false
+	meta.startAttempt()
+
+	return true
+}
+
+func (meta *ResponseMeta) release() {
+	atomic.StoreInt32(&meta.inUse, 0)
+}
+
+// startAttempt resets the fields describing the response before an attempt.
+func (meta *ResponseMeta) startAttempt() {
+	meta.Status = ""
+	meta.Message = ""
+	meta.Result = nil
+	meta.StatusCode = 0
+	meta.Header = nil
+}
+
+// TrackCall makes a call with f that is served by a JSON-RPC node rather
+// than Etherscan, recording its result into any ResponseMeta attached to ctx
+// with WithMeta in the same way as for Etherscan calls.
+func TrackCall(ctx context.Context, f func() (json.RawMessage, error)) (json.RawMessage, error) {
+	overrides, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+
+	var opts callOptions
+	for _, override := range overrides {
+		override(&opts)
+	}
+
+	if opts.meta == nil {
+		return f()
+	}
+
+	if !opts.meta.acquire() {
+		return nil, ErrMetaInUse
+	}
+	defer opts.meta.release()
+
+	start := time.Now()
+	result, err := f()
+
+	opts.meta.Attempts = 1
+	opts.meta.Elapsed = time.Since(start)
+	opts.meta.Node = true
+	opts.meta.Result = result
+
+	return result, err
+}
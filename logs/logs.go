@@ -20,6 +20,10 @@ type LogsClient struct {
 	API *httpapi.APIClient
 }
 
+// MaxLogsPerPage is the maximum number of logs returned by a single GetLogs
+// call.
+const MaxLogsPerPage = 1000
+
 // LogsRequest contains the request parameters for GetLogs.
 type LogsRequest struct {
 	FromBlock   LogsBlockParam
@@ -27,6 +31,7 @@ type LogsRequest struct {
 	Address     common.Address
 	Topics      []common.Hash
 	Comparisons []TopicComparison
+	ecommon.Pagination
 }
 
 func (req *LogsRequest) toParams() (map[string]string, error) {
@@ -54,6 +59,14 @@ func (req *LogsRequest) toParams() (map[string]string, error) {
 		return nil, err
 	}
 
+	if req.Page != 0 {
+		params["page"] = strconv.FormatUint(req.Page, 10)
+	}
+
+	if req.Offset != 0 {
+		params["offset"] = strconv.FormatUint(req.Offset, 10)
+	}
+
 	return params, nil
 }
 
@@ -125,7 +138,8 @@ type ComparisonOperator int32
 // LogResponse contains information on an ethereum log.
 type LogResponse struct {
 	Address          common.Address
-	BlockNumber      uint64 `etherscan:"blockNumber,hex"`
+	BlockNumber      uint64      `etherscan:"blockNumber,hex"`
+	BlockHash        common.Hash `etherscan:"blockHash,omitempty"`
 	Data             []byte
 	GasPrice         *big.Int  `etherscan:"gasPrice,hex"`
 	GasUsed          *big.Int  `etherscan:"gasUsed,hex"`
@@ -134,9 +148,14 @@ type LogResponse struct {
 	Topics           []common.Hash
 	TransactionHash  common.Hash `etherscan:"transactionHash"`
 	TransactionIndex uint32      `etherscan:"transactionIndex,hex"`
+
+	// Extra holds any response fields not decoded into the fields above.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
 }
 
-// GetLogs provides an alternative to the native eth_getLogs.
+// GetLogs provides an alternative to the native eth_getLogs. At most
+// MaxLogsPerPage logs are returned per call: use Page and Offset to page
+// through more, or GetAllLogs.
 func (c *LogsClient) GetLogs(ctx context.Context, req *LogsRequest) ([]LogResponse, error) {
 	params, err := req.toParams()
 	if err != nil {
@@ -159,3 +178,52 @@ func (c *LogsClient) GetLogs(ctx context.Context, req *LogsRequest) ([]LogRespon
 
 	return result, nil
 }
+
+// GetAllLogs returns all logs matching the request, making as many GetLogs
+// calls as needed. Logs are requested in pages of req.Offset, or
+// MaxLogsPerPage if unset, and req.Page is ignored. When a page is full, the
+// query continues from the block of its last log, so that the number of
+// logs in the block range is not limited by how far Etherscan allows paging.
+// If no logs match, nil is returned without an error.
+func (c *LogsClient) GetAllLogs(ctx context.Context, req *LogsRequest) ([]LogResponse, error) {
+	next := *req
+	next.Page = 1
+	if next.Offset == 0 {
+		next.Offset = MaxLogsPerPage
+	}
+
+	var result []LogResponse
+
+	for {
+		page, err := c.GetLogs(ctx, &next)
+		if httpapi.IsEmptyResult(err) {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if uint64(len(page)) < next.Offset {
+			return append(result, page...), nil
+		}
+
+		// If the page holds a single block, its logs are paged through.
+		// Otherwise the logs of its last block may continue on the next page,
+		// so they are requested again from that block.
+		last := page[len(page)-1].BlockNumber
+		if page[0].BlockNumber == last {
+			result = append(result, page...)
+			next.Page++
+
+			continue
+		}
+
+		for i := range page {
+			if page[i].BlockNumber < last {
+				result = append(result, page[i])
+			}
+		}
+
+		next.FromBlock = LogsBlockParam{Number: last}
+		next.Page = 1
+	}
+}
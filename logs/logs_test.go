@@ -2,13 +2,16 @@ package logs_test
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ryanc414/etherscan-api-go"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/ryanc414/etherscan-api-go/logs"
 	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,4 +62,21 @@ func TestLogs(t *testing.T) {
 
 		cupaloy.SnapshotT(t, logs)
 	})
+
+	t.Run("GetAllLogs", func(t *testing.T) {
+		result, err := client.Logs.GetAllLogs(ctx, &logs.LogsRequest{
+			FromBlock:  logs.LogsBlockParam{Number: 19500100},
+			ToBlock:    logs.LogsBlockParam{Number: 19500110},
+			Address:    common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+			Topics:     []common.Hash{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")},
+			Pagination: ecommon.Pagination{Offset: 2},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 5)
+
+		// Logs of a block split across pages are not duplicated.
+		for i, log := range result {
+			assert.Equal(t, common.BigToHash(big.NewInt(int64(0xabc001+i))), log.TransactionHash)
+		}
+	})
 }
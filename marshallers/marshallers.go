@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
 )
 
 func MarshalRequest(req interface{}) map[string]string {
@@ -26,11 +28,19 @@ func MarshalRequest(req interface{}) map[string]string {
 
 	res := make(map[string]string)
 
-	for i := 0; i < reqType.NumField(); i++ {
-		field := reqType.Field(i)
+	for _, field := range reflect.VisibleFields(reqType) {
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+
 		info := parseTag(field)
+		fieldVal := reqVal.FieldByIndex(field.Index)
+		if info.omitEmpty && fieldVal.IsZero() {
+			continue
+		}
+
 		key := keyName(field, &info)
-		val := formatValue(reqVal.Field(i), &info)
+		val := formatValue(fieldVal, &info)
 		if val != "" {
 			res[key] = val
 		}
@@ -63,6 +73,16 @@ func formatValue(fieldVal reflect.Value, info *tagInfo) string {
 		return v.String()
 	}
 
+	if v, ok := iVal.(common.Hash); ok && info.hex {
+		return hexutil.EncodeBig(v.Big())
+	}
+
+	// Block hash tags are rejected by the proxy client before reaching
+	// here, as Etherscan does not accept them.
+	if v, ok := iVal.(ecommon.BlockTag); ok {
+		return v.String()
+	}
+
 	if v, ok := iVal.(time.Time); ok {
 		if info.date {
 			return v.Format(dateFormat)
@@ -107,6 +127,9 @@ type tagInfo struct {
 	str   bool
 	sep   bool
 	comma bool
+	extra bool
+
+	omitEmpty bool
 }
 
 func parseTag(fieldType reflect.StructField) tagInfo {
@@ -139,6 +162,12 @@ func parseTag(fieldType reflect.StructField) tagInfo {
 
 		case "comma":
 			info.comma = true
+
+		case "omitempty":
+			info.omitEmpty = true
+
+		case "extra":
+			info.extra = true
 		}
 	}
 
@@ -1,9 +1,13 @@
 package marshallers
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type blockNumberAndIndex struct {
@@ -25,3 +29,88 @@ func TestRequestMarshaller(t *testing.T) {
 	}
 	assert.Equal(t, expected, res)
 }
+
+type pagination struct {
+	Page   uint64 `etherscan:"page,omitempty"`
+	Offset uint64 `etherscan:"offset,omitempty"`
+}
+
+type paginatedRequest struct {
+	StartBlock uint64
+	pagination
+}
+
+func TestRequestMarshallerEmbedded(t *testing.T) {
+	req := paginatedRequest{pagination: pagination{Page: 2}}
+
+	res := MarshalRequest(&req)
+
+	expected := map[string]string{
+		"startblock": "0",
+		"page":       "2",
+	}
+	assert.Equal(t, expected, res)
+}
+
+type withdrawal struct {
+	Index  uint64   `etherscan:"index,hex"`
+	Amount *big.Int `etherscan:"amount,hex"`
+}
+
+type accessTuple struct {
+	Address     common.Address
+	StorageKeys []common.Hash `etherscan:"storageKeys"`
+}
+
+type nestedResponse struct {
+	Nonce       *big.Int `etherscan:"nonce,hex"`
+	Withdrawals []withdrawal
+	AccessList  []accessTuple `etherscan:"accessList"`
+	Missing     uint64        `etherscan:"missing,hex,omitempty"`
+}
+
+func TestResponseUnmarshallerNested(t *testing.T) {
+	data := []byte(`{
+		"nonce": "0x000000000000002a",
+		"withdrawals": [{"index": "0x2a", "amount": "0xde0b6b3a7640000"}],
+		"accessList": [{
+			"address": "0x0000000000000000000000000000000000000001",
+			"storageKeys": ["0x0000000000000000000000000000000000000000000000000000000000000002"]
+		}]
+	}`)
+
+	var res nestedResponse
+	require.NoError(t, UnmarshalResponse(data, &res))
+
+	expected := nestedResponse{
+		Nonce: big.NewInt(42),
+		Withdrawals: []withdrawal{
+			{Index: 42, Amount: big.NewInt(1e18)},
+		},
+		AccessList: []accessTuple{{
+			Address:     common.HexToAddress("0x1"),
+			StorageKeys: []common.Hash{common.HexToHash("0x2")},
+		}},
+	}
+	assert.Equal(t, expected, res)
+}
+
+type blockTagRequest struct {
+	Number ecommon.BlockTag `etherscan:"number"`
+	Named  ecommon.BlockTag `etherscan:"named"`
+}
+
+func TestRequestMarshallerBlockTag(t *testing.T) {
+	req := blockTagRequest{
+		Number: ecommon.BlockTagNumber(123456),
+		Named:  ecommon.BlockTagNamed(ecommon.BlockParameterFinalized),
+	}
+
+	res := MarshalRequest(&req)
+
+	expected := map[string]string{
+		"number": "0x1e240",
+		"named":  "finalized",
+	}
+	assert.Equal(t, expected, res)
+}
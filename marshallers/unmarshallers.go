@@ -2,7 +2,6 @@ package marshallers
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -11,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -103,6 +101,34 @@ func (u *hexUint) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// hexBigInt is like hexutil.Big but allows leading zero digits, as in the
+// zero-padded nonce of post-merge blocks.
+type hexBigInt big.Int
+
+func (b *hexBigInt) UnmarshalJSON(data []byte) error {
+	var hexStr string
+	if err := json.Unmarshal(data, &hexStr); err != nil {
+		return err
+	}
+
+	digits := strings.TrimPrefix(hexStr, "0x")
+	if digits == hexStr || digits == "" {
+		return errors.Errorf("cannot parse %s as hex big.Int", hexStr)
+	}
+
+	val, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return errors.Errorf("cannot parse %s as hex big.Int", hexStr)
+	}
+
+	*b = hexBigInt(*val)
+	return nil
+}
+
+func (b *hexBigInt) unwrap() *big.Int {
+	return (*big.Int)(b)
+}
+
 type unixTimestamp time.Time
 
 func (t *unixTimestamp) UnmarshalJSON(data []byte) error {
@@ -132,16 +158,13 @@ func (t *hexTimestamp) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	buf := bytes.NewBuffer(common.Hex2BytesFixed(hex, 64))
-
-	var unixSeconds uint64
-	if err := binary.Read(buf, binary.BigEndian, &unixSeconds); err != nil {
-		return err
+	unixSeconds, err := strconv.ParseUint(strings.TrimPrefix(hex, "0x"), 16, 64)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse %s as hex timestamp", hex)
 	}
 
 	*t = hexTimestamp(time.Unix(int64(unixSeconds), 0))
 	return nil
-
 }
 
 func (t hexTimestamp) unwrap() time.Time {
@@ -218,6 +241,14 @@ func unmarshalSliceRsp(data []byte, v reflect.Value, info *tagInfo) error {
 	for i := range rawSlice {
 		el := slice.Index(i)
 
+		if el.Kind() != reflect.Struct {
+			if err := setFieldValue(el, rawSlice[i], info); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		if err := unmarshalStructRsp(rawSlice[i], el); err != nil {
 			return err
 		}
@@ -251,13 +282,24 @@ func unmarshalStringSepSlice(data []byte, v reflect.Value, sep string, info *tag
 	return nil
 }
 
+// unmarshalStructRsp unmarshals a JSON object into a struct. Structs may opt
+// in to capturing unrecognised fields with a map[string]json.RawMessage field,
+// or a type defined as one such as common.ExtraFields, tagged
+// `etherscan:",extra"`.
 func unmarshalStructRsp(data []byte, v reflect.Value) error {
 	var rspMap map[string]json.RawMessage
 	if err := json.Unmarshal(data, &rspMap); err != nil {
 		return errors.Wrap(err, "while unmarshalling as map")
 	}
 
+	// A null result, such as the proxy module returns for an unknown block
+	// or transaction, leaves the struct zeroed.
+	if rspMap == nil {
+		return nil
+	}
+
 	fieldTypes := reflect.VisibleFields(v.Type())
+	var extraField reflect.Value
 
 	for i := range fieldTypes {
 		fieldType := fieldTypes[i]
@@ -268,11 +310,20 @@ func unmarshalStructRsp(data []byte, v reflect.Value) error {
 		field := v.FieldByIndex(fieldType.Index)
 
 		info := parseTag(fieldType)
+		if info.extra {
+			extraField = field
+			continue
+		}
+
 		name := getFieldName(fieldType, &info)
 
 		fieldData := rspMap[name]
+		delete(rspMap, name)
+
 		if len(fieldData) == 0 {
-			log.Warn().Msgf("no field with name %s in response data", name)
+			if !info.omitEmpty {
+				log.Warn().Msgf("no field with name %s in response data", name)
+			}
 			continue
 		}
 
@@ -281,6 +332,10 @@ func unmarshalStructRsp(data []byte, v reflect.Value) error {
 		}
 	}
 
+	if extraField.IsValid() && len(rspMap) > 0 {
+		extraField.Set(reflect.ValueOf(rspMap).Convert(extraField.Type()))
+	}
+
 	return nil
 }
 
@@ -319,6 +374,10 @@ func setFieldValue(field reflect.Value, data []byte, info *tagInfo) error {
 		return unmarshalSliceRsp(data, field, info)
 	}
 
+	if field.Kind() == reflect.Struct && !isUnmarshaler(field.Type()) {
+		return unmarshalStructRsp(data, field)
+	}
+
 	into, setter := getTypeUnmarshler(field, data, info)
 	if into == nil {
 		return nil
@@ -327,6 +386,11 @@ func setFieldValue(field reflect.Value, data []byte, info *tagInfo) error {
 	return unmarshalField(data, into, field, setter)
 }
 
+func isUnmarshaler(t reflect.Type) bool {
+	var u json.Unmarshaler
+	return reflect.PtrTo(t).Implements(reflect.TypeOf(&u).Elem())
+}
+
 func setDirect(v interface{}, field reflect.Value) {
 	field.Set(reflect.ValueOf(v))
 }
@@ -337,8 +401,8 @@ func getTypeUnmarshler(
 	iField := field.Interface()
 	if _, ok := iField.(*big.Int); ok {
 		if info.hex {
-			return new(hexutil.Big), func(v interface{}) {
-				setDirect(v.(*hexutil.Big).ToInt(), field)
+			return new(hexBigInt), func(v interface{}) {
+				setDirect(v.(*hexBigInt).unwrap(), field)
 			}
 		}
 
@@ -0,0 +1,272 @@
+// Package proxies resolves the implementation contracts behind proxies, from
+// their storage, Etherscan's contract info and their upgrade events.
+package proxies
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/contracts"
+	"github.com/ryanc414/etherscan-api-go/logs"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+	"github.com/ryanc414/etherscan-api-go/storage"
+)
+
+// ErrNotProxy is returned when no implementation can be found for a contract.
+var ErrNotProxy = errors.New("contract is not a proxy")
+
+var (
+	// upgradedTopic is the topic of Upgraded(address), emitted when a proxy's
+	// implementation changes.
+	upgradedTopic = common.HexToHash("0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b")
+
+	// beaconUpgradedTopic is the topic of BeaconUpgraded(address), emitted
+	// when a beacon proxy's beacon changes.
+	beaconUpgradedTopic = common.HexToHash("0x1cf3b03a6cf19fa2baba4df148e9dcabedea7f8a5c07840e207e5c089be95d3e")
+
+	// implementationSelector is the selector of implementation(), which
+	// returns the implementation of a beacon.
+	implementationSelector = []byte{0x5c, 0x60, 0xda, 0x1b}
+)
+
+// Source identifies how an implementation was resolved.
+type Source string
+
+const (
+	SourceEIP1967       Source = "eip1967"
+	SourceEIP1967Beacon Source = "eip1967-beacon"
+	SourceEIP1822       Source = "eip1822"
+	SourceOZLegacy      Source = "oz-legacy"
+	// SourceEtherscan is set if the implementation is only known from
+	// Etherscan's contract info, e.g. for minimal proxies.
+	SourceEtherscan Source = "etherscan"
+	// SourceUpgradeEvent is set if the implementation is only known from the
+	// latest Upgraded event, for contracts that Etherscan marks as proxies
+	// without naming an implementation. This is a best guess: it is stale
+	// if the proxy has since changed its implementation without an event.
+	SourceUpgradeEvent Source = "upgrade-event"
+)
+
+// Resolution is the resolved implementation of a proxy.
+type Resolution struct {
+	Proxy          common.Address
+	Implementation common.Address
+	Source         Source
+	// Beacon is the beacon of a beacon proxy, or nil for other proxies.
+	Beacon *common.Address
+	// History holds the proxy's upgrades, oldest first.
+	History []Upgrade
+}
+
+// Upgrade is a change of a proxy's implementation or beacon.
+type Upgrade struct {
+	// Implementation is the new implementation, or the new beacon if Beacon
+	// is set.
+	Implementation  common.Address
+	Beacon          bool
+	BlockNumber     uint64
+	Timestamp       time.Time
+	TransactionHash common.Hash
+	LogIndex        uint32
+}
+
+// Resolver resolves proxy implementations.
+type Resolver struct {
+	Contracts *contracts.ContractsClient
+	Proxy     *proxy.ProxyClient
+	Logs      *logs.LogsClient
+}
+
+// Resolve returns the current implementation of a proxy and its history of
+// upgrades. The implementation is read from the well-known proxy storage
+// slots, falling back to Etherscan's contract info and then, only if
+// Etherscan marks the contract as a proxy, to the latest upgrade event.
+// ErrNotProxy is returned if none of these identify one.
+func (r *Resolver) Resolve(ctx context.Context, address common.Address) (*Resolution, error) {
+	res := &Resolution{Proxy: address}
+
+	if err := r.resolveSlots(ctx, res); err != nil {
+		return nil, err
+	}
+
+	// A contract with cleared slots but old upgrade events, e.g. one that
+	// is no longer a proxy, is only trusted to be a proxy if Etherscan
+	// marks it as one.
+	var markedProxy bool
+	if res.Implementation == (common.Address{}) {
+		var err error
+		if markedProxy, err = r.resolveContractInfo(ctx, res); err != nil {
+			return nil, err
+		}
+	}
+
+	history, err := r.History(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	res.History = history
+
+	if res.Implementation == (common.Address{}) && markedProxy {
+		for i := len(history) - 1; i >= 0; i-- {
+			if !history[i].Beacon {
+				res.Implementation = history[i].Implementation
+				res.Source = SourceUpgradeEvent
+				break
+			}
+		}
+	}
+
+	if res.Implementation == (common.Address{}) {
+		return nil, errors.Wrapf(ErrNotProxy, "contract %s", address)
+	}
+
+	return res, nil
+}
+
+// resolveSlots reads the implementation from the proxy storage slots, in
+// order of precedence.
+func (r *Resolver) resolveSlots(ctx context.Context, res *Resolution) error {
+	reader := storage.Reader{Proxy: r.Proxy}
+	latest := ecommon.BlockTagNamed(ecommon.BlockParameterLatest)
+
+	slots := []struct {
+		slot   common.Hash
+		source Source
+	}{
+		{storage.EIP1967ImplementationSlot, SourceEIP1967},
+		{storage.EIP1967BeaconSlot, SourceEIP1967Beacon},
+		{storage.EIP1822ProxiableSlot, SourceEIP1822},
+		{storage.OZLegacyImplementationSlot, SourceOZLegacy},
+	}
+
+	for _, s := range slots {
+		address, err := reader.ReadAddress(ctx, res.Proxy, s.slot, latest)
+		if err != nil {
+			return errors.Wrapf(err, "while reading %s slot", s.source)
+		}
+
+		if address == (common.Address{}) {
+			continue
+		}
+
+		res.Source = s.source
+
+		if s.source != SourceEIP1967Beacon {
+			res.Implementation = address
+			return nil
+		}
+
+		res.Beacon = &address
+		res.Implementation, err = r.beaconImplementation(ctx, address, latest)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Resolver) beaconImplementation(
+	ctx context.Context, beacon common.Address, tag ecommon.BlockTag,
+) (common.Address, error) {
+	result, err := r.Proxy.Call(ctx, &proxy.CallRequest{
+		To:   beacon,
+		Data: implementationSelector,
+		Tag:  tag,
+	})
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "while calling beacon %s", beacon)
+	}
+
+	if len(result) != common.HashLength {
+		return common.Address{}, errors.Errorf("unexpected implementation() result from beacon %s", beacon)
+	}
+
+	return common.BytesToAddress(result), nil
+}
+
+// resolveContractInfo reads the implementation from Etherscan's contract
+// info, returning whether Etherscan marks the contract as a proxy.
+func (r *Resolver) resolveContractInfo(ctx context.Context, res *Resolution) (bool, error) {
+	info, err := r.Contracts.GetContractSourceCode(ctx, res.Proxy)
+	if err != nil {
+		return false, err
+	}
+
+	if len(info) == 0 || !info[0].Proxy {
+		return false, nil
+	}
+
+	if info[0].Implementation != nil {
+		res.Implementation = *info[0].Implementation
+		res.Source = SourceEtherscan
+	}
+
+	return true, nil
+}
+
+// History returns a proxy's upgrades from its Upgraded and BeaconUpgraded
+// events, oldest first. Events are paged through, so proxies with more
+// upgrades than fit in a single page of logs are fully covered.
+func (r *Resolver) History(ctx context.Context, address common.Address) ([]Upgrade, error) {
+	var history []Upgrade
+
+	for _, topic := range []common.Hash{upgradedTopic, beaconUpgradedTopic} {
+		events, err := r.Logs.GetAllLogs(ctx, &logs.LogsRequest{
+			ToBlock: logs.LogsBlockParam{Latest: true},
+			Address: address,
+			Topics:  []common.Hash{topic},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting upgrade events")
+		}
+
+		for i := range events {
+			upgrade, ok := parseUpgrade(&events[i])
+			if ok {
+				history = append(history, upgrade)
+			}
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].BlockNumber != history[j].BlockNumber {
+			return history[i].BlockNumber < history[j].BlockNumber
+		}
+
+		return history[i].LogIndex < history[j].LogIndex
+	})
+
+	return history, nil
+}
+
+// parseUpgrade parses an upgrade event. The address is usually indexed, but
+// some proxies, such as USDC's, emit it in the log data instead.
+func parseUpgrade(event *logs.LogResponse) (Upgrade, bool) {
+	if len(event.Topics) == 0 {
+		return Upgrade{}, false
+	}
+
+	upgrade := Upgrade{
+		Beacon:          event.Topics[0] == beaconUpgradedTopic,
+		BlockNumber:     event.BlockNumber,
+		Timestamp:       event.Timestamp,
+		TransactionHash: event.TransactionHash,
+		LogIndex:        event.LogIndex,
+	}
+
+	switch {
+	case len(event.Topics) == 2:
+		upgrade.Implementation = common.BytesToAddress(event.Topics[1].Bytes())
+
+	case len(event.Topics) == 1 && len(event.Data) == common.HashLength:
+		upgrade.Implementation = common.BytesToAddress(event.Data)
+
+	default:
+		return Upgrade{}, false
+	}
+
+	return upgrade, true
+}
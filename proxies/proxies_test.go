@@ -0,0 +1,90 @@
+package proxies_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/proxies"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	m := testbed.NewMockServer("proxies", false)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+	})
+
+	resolver := proxies.Resolver{
+		Contracts: &client.Contracts,
+		Proxy:     &client.Proxy,
+		Logs:      &client.Logs,
+	}
+
+	ctx := context.Background()
+
+	t.Run("OZLegacy", func(t *testing.T) {
+		res, err := resolver.Resolve(ctx, common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"))
+		require.NoError(t, err)
+
+		assert.Equal(t, proxies.SourceOZLegacy, res.Source)
+		assert.Equal(t, common.HexToAddress("0x43506849d7c04f9138d1a2050bbf3a0c054402dd"), res.Implementation)
+		require.Len(t, res.History, 3)
+		assert.Equal(t, res.Implementation, res.History[2].Implementation)
+
+		cupaloy.SnapshotT(t, res)
+	})
+
+	t.Run("Beacon", func(t *testing.T) {
+		res, err := resolver.Resolve(ctx, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+		require.NoError(t, err)
+
+		beacon := common.HexToAddress("0x2222222222222222222222222222222222222222")
+		assert.Equal(t, proxies.SourceEIP1967Beacon, res.Source)
+		assert.Equal(t, &beacon, res.Beacon)
+		assert.Equal(t, common.HexToAddress("0x3333333333333333333333333333333333333333"), res.Implementation)
+
+		require.Len(t, res.History, 1)
+		assert.True(t, res.History[0].Beacon)
+		assert.Equal(t, beacon, res.History[0].Implementation)
+	})
+
+	t.Run("Etherscan", func(t *testing.T) {
+		res, err := resolver.Resolve(ctx, common.HexToAddress("0x4444444444444444444444444444444444444444"))
+		require.NoError(t, err)
+
+		assert.Equal(t, proxies.SourceEtherscan, res.Source)
+		assert.Equal(t, common.HexToAddress("0x5555555555555555555555555555555555555555"), res.Implementation)
+		assert.Empty(t, res.History)
+	})
+
+	t.Run("UpgradeEvent", func(t *testing.T) {
+		res, err := resolver.Resolve(ctx, common.HexToAddress("0x6666666666666666666666666666666666666666"))
+		require.NoError(t, err)
+
+		assert.Equal(t, proxies.SourceUpgradeEvent, res.Source)
+		assert.Equal(t, common.HexToAddress("0x8888888888888888888888888888888888888888"), res.Implementation)
+	})
+
+	t.Run("StaleUpgradeEvent", func(t *testing.T) {
+		// The slots are clear and Etherscan does not mark the contract as a
+		// proxy, so its old upgrade event is not used.
+		_, err := resolver.Resolve(ctx, common.HexToAddress("0x7777777777777777777777777777777777777777"))
+		assert.ErrorIs(t, err, proxies.ErrNotProxy)
+	})
+
+	t.Run("NotProxy", func(t *testing.T) {
+		_, err := resolver.Resolve(ctx, common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"))
+		assert.ErrorIs(t, err, proxies.ErrNotProxy)
+	})
+}
@@ -0,0 +1,480 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+	"github.com/pkg/errors"
+)
+
+// ErrHashMismatch is matched by errors.Is for any HashMismatchError.
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// ErrUnsupportedTxType is returned when converting a transaction of a type
+// that go-ethereum's types cannot represent.
+var ErrUnsupportedTxType = errors.New("unsupported transaction type")
+
+// SetCodeTxType is the type of EIP-7702 set-code transactions, which
+// go-ethereum's types do not support.
+const SetCodeTxType = 0x04
+
+// HashMismatchError is returned by the go-ethereum type converters when a
+// hash or root recomputed from the converted data does not match the value
+// reported by the API.
+type HashMismatchError struct {
+	// What names the mismatched hash, e.g. "block hash".
+	What     string
+	Expected common.Hash
+	Actual   common.Hash
+}
+
+func (err *HashMismatchError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s is %s, expected %s", ErrHashMismatch, err.What, err.Actual, err.Expected,
+	)
+}
+
+// Is allows the error to be matched against ErrHashMismatch.
+func (err *HashMismatchError) Is(target error) bool {
+	return target == ErrHashMismatch
+}
+
+func verifyHash(what string, expected, actual common.Hash) error {
+	if expected != actual {
+		return &HashMismatchError{What: what, Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
+
+// Header converts the block info to a go-ethereum header and verifies that
+// its hash matches the block hash.
+func (b *ProxyBaseBlockInfo) Header() (*types.Header, error) {
+	header := &types.Header{
+		ParentHash:  b.ParentHash,
+		UncleHash:   b.SHA3Uncles,
+		Coinbase:    b.Miner,
+		Root:        b.StateRoot,
+		TxHash:      b.TransactionsRoot,
+		ReceiptHash: b.ReceiptsRoot,
+		Bloom:       types.BytesToBloom(b.LogsBloom),
+		Difficulty:  bigOrZero(b.Difficulty),
+		Number:      new(big.Int).SetUint64(b.Number),
+		GasLimit:    bigOrZero(b.GasLimit).Uint64(),
+		GasUsed:     bigOrZero(b.GasUsed).Uint64(),
+		Time:        uint64(b.Timestamp.Unix()),
+		Extra:       b.ExtraData,
+		MixDigest:   b.MixHash,
+		Nonce:       types.EncodeNonce(bigOrZero(b.Nonce).Uint64()),
+		BaseFee:     b.BaseFeePerGas,
+	}
+
+	// Optional header fields are only encoded up to the latest upgrade that
+	// the block includes, so they are set according to which are present.
+	if b.WithdrawalsRoot != (common.Hash{}) {
+		withdrawalsRoot := b.WithdrawalsRoot
+		header.WithdrawalsHash = &withdrawalsRoot
+	}
+
+	if b.ParentBeaconBlockRoot != (common.Hash{}) {
+		blobGasUsed, excessBlobGas := b.BlobGasUsed, b.ExcessBlobGas
+		parentBeaconRoot := b.ParentBeaconBlockRoot
+
+		header.BlobGasUsed = &blobGasUsed
+		header.ExcessBlobGas = &excessBlobGas
+		header.ParentBeaconRoot = &parentBeaconRoot
+	}
+
+	if b.RequestsHash != (common.Hash{}) {
+		requestsHash := b.RequestsHash
+		header.RequestsHash = &requestsHash
+	}
+
+	if err := verifyHash("block hash", b.Hash, header.Hash()); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// Block converts the block info to a go-ethereum block. The block hash,
+// transaction hashes and the transactions and withdrawals roots are verified.
+// Only uncle hashes are available from the API, so the block has no uncle
+// headers.
+//
+// EIP-7702 set-code transactions cannot be represented by go-ethereum's
+// types, so ErrUnsupportedTxType is returned for blocks that include them,
+// once they have been verified against their hashes and the transactions
+// root.
+func (b *ProxyFullBlockInfo) Block() (*types.Block, error) {
+	header, err := b.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make(types.Transactions, 0, len(b.Transactions))
+	encoded := make(encodedList, len(b.Transactions))
+	setCodeTx := -1
+
+	for i := range b.Transactions {
+		info := &b.Transactions[i]
+
+		if info.Type == SetCodeTxType {
+			encoded[i], err = info.setCodeTxEncoding()
+			if err != nil {
+				return nil, errors.Wrapf(err, "while verifying transaction %d", i)
+			}
+
+			if setCodeTx < 0 {
+				setCodeTx = i
+			}
+
+			continue
+		}
+
+		tx, err := info.Transaction()
+		if err != nil {
+			return nil, errors.Wrapf(err, "while converting transaction %d", i)
+		}
+
+		encoded[i], err = tx.MarshalBinary()
+		if err != nil {
+			return nil, errors.Wrapf(err, "while encoding transaction %d", i)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	txRoot := types.DeriveSha(encoded, trie.NewStackTrie(nil))
+	if err := verifyHash("transactions root", header.TxHash, txRoot); err != nil {
+		return nil, err
+	}
+
+	if setCodeTx >= 0 {
+		return nil, errors.Wrapf(ErrUnsupportedTxType, "transaction %d has type %d", setCodeTx, SetCodeTxType)
+	}
+
+	var withdrawals types.Withdrawals
+	if header.WithdrawalsHash != nil {
+		withdrawals = make(types.Withdrawals, len(b.Withdrawals))
+		for i := range b.Withdrawals {
+			withdrawals[i] = b.Withdrawals[i].withdrawal()
+		}
+
+		withdrawalsRoot := types.DeriveSha(withdrawals, trie.NewStackTrie(nil))
+		if err := verifyHash("withdrawals root", *header.WithdrawalsHash, withdrawalsRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	body := types.Body{Transactions: txs, Withdrawals: withdrawals}
+	return types.NewBlockWithHeader(header).WithBody(body), nil
+}
+
+// encodedList is a list of consensus-encoded transactions or receipts, from
+// which the transactions or receipts root is derived.
+type encodedList [][]byte
+
+func (l encodedList) Len() int {
+	return len(l)
+}
+
+func (l encodedList) EncodeIndex(i int, w *bytes.Buffer) {
+	w.Write(l[i])
+}
+
+func (w *Withdrawal) withdrawal() *types.Withdrawal {
+	return &types.Withdrawal{
+		Index:     w.Index,
+		Validator: w.ValidatorIndex,
+		Address:   w.Address,
+		Amount:    w.Amount,
+	}
+}
+
+// Transaction converts the transaction info to a go-ethereum transaction and
+// verifies that its hash matches the transaction hash. ErrUnsupportedTxType
+// is returned for transaction types that go-ethereum cannot represent, such
+// as SetCodeTxType.
+func (tx *ProxyTransactionInfo) Transaction() (*types.Transaction, error) {
+	to := &tx.To
+	if tx.To == (common.Address{}) {
+		to = nil
+	}
+
+	converted, err := tx.transaction(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// A contract creation and a transfer to the zero address are decoded
+	// alike, so the hash decides between them.
+	if converted.Hash() != tx.Hash && to == nil && tx.Type != types.BlobTxType {
+		converted, err = tx.transaction(&tx.To)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := verifyHash("transaction hash", tx.Hash, converted.Hash()); err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+func (tx *ProxyTransactionInfo) transaction(to *common.Address) (*types.Transaction, error) {
+	gas := bigOrZero(tx.Gas).Uint64()
+	v := new(big.Int).SetUint64(uint64(tx.V))
+
+	switch tx.Type {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce,
+			GasPrice: tx.GasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    tx.Value,
+			Data:     tx.Input,
+			V:        v,
+			R:        tx.R,
+			S:        tx.S,
+		}), nil
+
+	case types.AccessListTxType:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasPrice:   tx.GasPrice,
+			Gas:        gas,
+			To:         to,
+			Value:      tx.Value,
+			Data:       tx.Input,
+			AccessList: tx.accessList(),
+			V:          v,
+			R:          tx.R,
+			S:          tx.S,
+		}), nil
+
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasTipCap:  tx.MaxPriorityFeePerGas,
+			GasFeeCap:  tx.MaxFeePerGas,
+			Gas:        gas,
+			To:         to,
+			Value:      tx.Value,
+			Data:       tx.Input,
+			AccessList: tx.accessList(),
+			V:          v,
+			R:          tx.R,
+			S:          tx.S,
+		}), nil
+
+	case types.BlobTxType:
+		return tx.blobTransaction(gas, v)
+
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedTxType, "type %d", tx.Type)
+	}
+}
+
+func (tx *ProxyTransactionInfo) blobTransaction(gas uint64, v *big.Int) (*types.Transaction, error) {
+	values := []*big.Int{
+		tx.ChainID, tx.MaxPriorityFeePerGas, tx.MaxFeePerGas, tx.Value,
+		tx.MaxFeePerBlobGas, v, tx.R, tx.S,
+	}
+	converted := make([]*uint256.Int, len(values))
+
+	for i, val := range values {
+		u, overflow := uint256.FromBig(bigOrZero(val))
+		if overflow {
+			return nil, errors.Errorf("value %s overflows 256 bits", val)
+		}
+
+		converted[i] = u
+	}
+
+	return types.NewTx(&types.BlobTx{
+		ChainID:    converted[0],
+		Nonce:      tx.Nonce,
+		GasTipCap:  converted[1],
+		GasFeeCap:  converted[2],
+		Gas:        gas,
+		To:         tx.To,
+		Value:      converted[3],
+		Data:       tx.Input,
+		AccessList: tx.accessList(),
+		BlobFeeCap: converted[4],
+		BlobHashes: tx.BlobVersionedHashes,
+		V:          converted[5],
+		R:          converted[6],
+		S:          converted[7],
+	}), nil
+}
+
+// setCodeTx is the consensus encoding of an EIP-7702 set-code transaction.
+type setCodeTx struct {
+	ChainID           *big.Int
+	Nonce             uint64
+	GasTipCap         *big.Int
+	GasFeeCap         *big.Int
+	Gas               uint64
+	To                common.Address
+	Value             *big.Int
+	Data              []byte
+	AccessList        types.AccessList
+	AuthorizationList []setCodeAuthorization
+	V, R, S           *big.Int
+}
+
+type setCodeAuthorization struct {
+	ChainID *big.Int
+	Address common.Address
+	Nonce   uint64
+	V       uint8
+	R, S    *big.Int
+}
+
+// setCodeTxEncoding returns the consensus encoding of a set-code transaction
+// and verifies that it hashes to the transaction hash.
+func (tx *ProxyTransactionInfo) setCodeTxEncoding() ([]byte, error) {
+	inner := setCodeTx{
+		ChainID:           bigOrZero(tx.ChainID),
+		Nonce:             tx.Nonce,
+		GasTipCap:         bigOrZero(tx.MaxPriorityFeePerGas),
+		GasFeeCap:         bigOrZero(tx.MaxFeePerGas),
+		Gas:               bigOrZero(tx.Gas).Uint64(),
+		To:                tx.To,
+		Value:             bigOrZero(tx.Value),
+		Data:              tx.Input,
+		AccessList:        tx.accessList(),
+		AuthorizationList: make([]setCodeAuthorization, len(tx.AuthorizationList)),
+		V:                 new(big.Int).SetUint64(uint64(tx.V)),
+		R:                 bigOrZero(tx.R),
+		S:                 bigOrZero(tx.S),
+	}
+
+	for i, auth := range tx.AuthorizationList {
+		inner.AuthorizationList[i] = setCodeAuthorization{
+			ChainID: bigOrZero(auth.ChainID),
+			Address: auth.Address,
+			Nonce:   auth.Nonce,
+			V:       uint8(auth.YParity),
+			R:       bigOrZero(auth.R),
+			S:       bigOrZero(auth.S),
+		}
+	}
+
+	payload, err := rlp.EncodeToBytes(&inner)
+	if err != nil {
+		return nil, errors.Wrap(err, "while encoding set-code transaction")
+	}
+
+	encoded := append([]byte{SetCodeTxType}, payload...)
+	if err := verifyHash("transaction hash", tx.Hash, crypto.Keccak256Hash(encoded)); err != nil {
+		return nil, err
+	}
+
+	return encoded, nil
+}
+
+func (tx *ProxyTransactionInfo) accessList() types.AccessList {
+	accessList := make(types.AccessList, len(tx.AccessList))
+	for i := range tx.AccessList {
+		accessList[i] = types.AccessTuple{
+			Address:     tx.AccessList[i].Address,
+			StorageKeys: tx.AccessList[i].StorageKeys,
+		}
+	}
+
+	return accessList
+}
+
+// Receipt converts the transaction receipt to a go-ethereum receipt and
+// verifies that its logs bloom matches the logs.
+func (r *ProxyTransactionReceipt) Receipt() (*types.Receipt, error) {
+	receipt := &types.Receipt{
+		Type:              uint8(r.Type),
+		PostState:         r.Root,
+		CumulativeGasUsed: bigOrZero(r.CumulativeGasUsed).Uint64(),
+		Bloom:             types.BytesToBloom(r.LogsBloom),
+		Logs:              make([]*types.Log, len(r.Logs)),
+		TxHash:            r.TransactionHash,
+		GasUsed:           bigOrZero(r.GasUsed).Uint64(),
+		EffectiveGasPrice: r.EffectiveGasPrice,
+		BlobGasUsed:       r.BlobGasUsed,
+		BlobGasPrice:      r.BlobGasPrice,
+		BlockHash:         r.BlockHash,
+		BlockNumber:       new(big.Int).SetUint64(r.BlockNumber),
+		TransactionIndex:  uint(r.TransactionIndex),
+	}
+
+	if len(r.Root) == 0 {
+		receipt.Status = types.ReceiptStatusFailed
+		if r.Status {
+			receipt.Status = types.ReceiptStatusSuccessful
+		}
+	}
+
+	if r.ContractAddress != nil {
+		receipt.ContractAddress = *r.ContractAddress
+	}
+
+	for i := range r.Logs {
+		receipt.Logs[i] = r.Logs[i].log()
+	}
+
+	if types.CreateBloom(types.Receipts{receipt}) != receipt.Bloom {
+		return nil, errors.Errorf("logs bloom of receipt %s does not match its logs", r.TransactionHash)
+	}
+
+	return receipt, nil
+}
+
+func (l *ProxyTxLog) log() *types.Log {
+	return &types.Log{
+		Address:     l.Address,
+		Topics:      l.Topics,
+		Data:        l.Data,
+		BlockNumber: l.BlockNumber,
+		TxHash:      l.TransactionHash,
+		TxIndex:     uint(l.TransactionIndex),
+		BlockHash:   l.BlockHash,
+		Index:       uint(l.LogIndex),
+		Removed:     l.Removed,
+	}
+}
+
+// VerifyReceipts checks that the receipts, which must be all those of the
+// block in order, match the block's receipts root. Receipts of set-code
+// transactions are supported, although types.Receipts cannot encode them.
+func VerifyReceipts(header *types.Header, receipts types.Receipts) error {
+	encoded := make(encodedList, len(receipts))
+	for i, receipt := range receipts {
+		var err error
+		encoded[i], err = receipt.MarshalBinary()
+		if err != nil {
+			return errors.Wrapf(err, "while encoding receipt %d", i)
+		}
+	}
+
+	root := types.DeriveSha(encoded, trie.NewStackTrie(nil))
+	return verifyHash("receipts root", header.ReceiptHash, root)
+}
+
+func bigOrZero(val *big.Int) *big.Int {
+	if val == nil {
+		return new(big.Int)
+	}
+
+	return val
+}
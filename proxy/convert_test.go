@@ -0,0 +1,155 @@
+package proxy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	m := testbed.NewMockServer("proxy", true)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+	})
+
+	ctx := context.Background()
+
+	t.Run("Block", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberFull(ctx, 19500000)
+		require.NoError(t, err)
+
+		block, err := info.Block()
+		require.NoError(t, err)
+
+		assert.Equal(t, info.Hash, block.Hash())
+		assert.Equal(t, uint64(19500000), block.NumberU64())
+		require.Len(t, block.Transactions(), len(typedTxHashes))
+		assert.Len(t, block.Withdrawals(), len(info.Withdrawals))
+		assert.NotNil(t, block.Header().ParentBeaconRoot)
+
+		for i, tx := range block.Transactions() {
+			assert.Equal(t, uint8(i), tx.Type())
+			assert.Equal(t, common.HexToHash(typedTxHashes[i]), tx.Hash())
+		}
+
+		assert.Len(t, block.Transactions()[types.BlobTxType].BlobHashes(), 2)
+	})
+
+	t.Run("PragueBlock", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberFull(ctx, 22500000)
+		require.NoError(t, err)
+
+		// The set-code transaction is verified, but cannot be included in
+		// the block.
+		_, err = info.Block()
+		assert.ErrorIs(t, err, proxy.ErrUnsupportedTxType)
+		assert.NotErrorIs(t, err, proxy.ErrHashMismatch)
+
+		require.Len(t, info.Transactions, 2)
+		setCode := info.Transactions[1]
+		assert.Equal(t, uint32(proxy.SetCodeTxType), setCode.Type)
+		require.Len(t, setCode.AuthorizationList, 1)
+
+		_, err = setCode.Transaction()
+		assert.ErrorIs(t, err, proxy.ErrUnsupportedTxType)
+
+		info.Transactions[1].AuthorizationList[0].Nonce++
+
+		_, err = info.Block()
+		assert.ErrorIs(t, err, proxy.ErrHashMismatch)
+	})
+
+	t.Run("PragueHeader", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberSummary(ctx, 22500000)
+		require.NoError(t, err)
+
+		header, err := info.Header()
+		require.NoError(t, err)
+		assert.Equal(t, info.Hash, header.Hash())
+		assert.NotNil(t, header.RequestsHash)
+	})
+
+	t.Run("LegacyHeader", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberSummary(ctx, 68943)
+		require.NoError(t, err)
+
+		header, err := info.Header()
+		require.NoError(t, err)
+		assert.Equal(t, info.Hash, header.Hash())
+		assert.Nil(t, header.BaseFee)
+	})
+
+	t.Run("HashMismatch", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberFull(ctx, 19500000)
+		require.NoError(t, err)
+
+		info.Transactions[1].Nonce++
+
+		_, err = info.Block()
+		assert.ErrorIs(t, err, proxy.ErrHashMismatch)
+
+		var mismatch *proxy.HashMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, "transaction hash", mismatch.What)
+	})
+
+	t.Run("Receipts", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberFull(ctx, 19500000)
+		require.NoError(t, err)
+
+		header, err := info.Header()
+		require.NoError(t, err)
+
+		receipts := make(types.Receipts, len(typedTxHashes))
+		for i, hash := range typedTxHashes {
+			receiptInfo, err := client.Proxy.GetTransactionReceipt(ctx, common.HexToHash(hash))
+			require.NoError(t, err)
+
+			receipts[i], err = receiptInfo.Receipt()
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, proxy.VerifyReceipts(header, receipts))
+
+		err = proxy.VerifyReceipts(header, receipts[:3])
+		assert.ErrorIs(t, err, proxy.ErrHashMismatch)
+	})
+
+	t.Run("PragueReceipts", func(t *testing.T) {
+		info, err := client.Proxy.GetBlockByNumberFull(ctx, 22500000)
+		require.NoError(t, err)
+
+		header, err := info.Header()
+		require.NoError(t, err)
+
+		receipts := make(types.Receipts, len(info.Transactions))
+		for i := range info.Transactions {
+			receiptInfo, err := client.Proxy.GetTransactionReceipt(ctx, info.Transactions[i].Hash)
+			require.NoError(t, err)
+
+			receipts[i], err = receiptInfo.Receipt()
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, uint8(proxy.SetCodeTxType), receipts[1].Type)
+		require.NoError(t, proxy.VerifyReceipts(header, receipts))
+
+		receipts[1].CumulativeGasUsed++
+
+		err = proxy.VerifyReceipts(header, receipts)
+		assert.ErrorIs(t, err, proxy.ErrHashMismatch)
+	})
+}
@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/httpapi"
+	"github.com/ryanc414/etherscan-api-go/marshallers"
+)
+
+// codeLimitExceeded is the JSON-RPC error code returned by nodes when a
+// request exceeds their rate limit.
+const codeLimitExceeded = -32005
+
+// proxyCall describes a proxy method call in both of the forms that it can
+// be served: as Etherscan query parameters and as JSON-RPC positional args.
+type proxyCall struct {
+	action  string
+	request interface{}
+	args    []interface{}
+	result  interface{}
+}
+
+// hasHashTag returns whether any of the call's args is a block hash tag,
+// which Etherscan does not accept.
+func (call *proxyCall) hasHashTag() bool {
+	for _, arg := range call.args {
+		if tag, ok := arg.(ecommon.BlockTag); ok {
+			if _, isHash := tag.Hash(); isHash {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// call serves a proxy method from Etherscan and/or the node Provider,
+// according to the configured policy. If the primary source is unavailable,
+// the call is retried against the fallback.
+func (c *ProxyClient) call(ctx context.Context, call *proxyCall) error {
+	if c.Node == nil {
+		return c.callEtherscan(ctx, call)
+	}
+
+	switch c.Policy {
+	case ecommon.ProviderPolicyNodeOnly:
+		return c.callNode(ctx, call)
+
+	case ecommon.ProviderPolicyEtherscanFirst:
+		return c.withFallback(ctx, call, c.callEtherscan, c.callNode)
+
+	case ecommon.ProviderPolicyNodeFirst:
+		return c.withFallback(ctx, call, c.callNode, c.callEtherscan)
+
+	default:
+		return c.callEtherscan(ctx, call)
+	}
+}
+
+type callFunc func(context.Context, *proxyCall) error
+
+func (c *ProxyClient) withFallback(
+	ctx context.Context, call *proxyCall, primary, fallback callFunc,
+) error {
+	err := primary(ctx, call)
+	if err == nil || ctx.Err() != nil || !isUnavailable(err) {
+		return err
+	}
+
+	log.Warn().Err(err).Str("method", call.action).Msg("primary provider failed, trying fallback")
+
+	if fallbackErr := fallback(ctx, call); fallbackErr != nil {
+		return errors.Wrapf(fallbackErr, "fallback failed after primary error (%v)", err)
+	}
+
+	return nil
+}
+
+// isUnavailable returns whether err means that a source could not serve a
+// call, in which case the fallback is tried: a transport error, an HTTP 429
+// or 5xx response or a rate limit, the circuit breaker or daily budget
+// stopping calls to Etherscan, or a block hash tag that Etherscan does not
+// accept. Errors about the call itself, such as reverts or invalid params,
+// would recur on the fallback and are returned as is.
+func isUnavailable(err error) bool {
+	if httpapi.IsTemporary(err) ||
+		errors.Is(err, httpapi.ErrCircuitOpen) ||
+		errors.Is(err, httpapi.ErrBudgetExhausted) ||
+		errors.Is(err, ecommon.ErrHashTagUnsupported) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == codeLimitExceeded
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func (c *ProxyClient) callEtherscan(ctx context.Context, call *proxyCall) error {
+	if call.hasHashTag() {
+		return errors.Wrapf(ecommon.ErrHashTagUnsupported, "while calling %s", call.action)
+	}
+
+	return c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.ProxyModule,
+		Action:  call.action,
+		Request: call.request,
+		Result:  call.result,
+	})
+}
+
+func (c *ProxyClient) callNode(ctx context.Context, call *proxyCall) error {
+	raw, err := httpapi.TrackCall(ctx, func() (json.RawMessage, error) {
+		var raw json.RawMessage
+		err := c.Node.CallContext(ctx, &raw, call.action, call.args...)
+		return raw, err
+	})
+	if err != nil {
+		return err
+	}
+
+	return marshallers.UnmarshalResponse(raw, call.result)
+}
@@ -7,22 +7,25 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/ryanc414/etherscan-api-go/httpapi"
 )
 
-// ProxyClient is the client for ethereum proxy actions.
+// ProxyClient is the client for ethereum proxy actions. Methods may
+// optionally be served by a JSON-RPC node, according to Policy.
 type ProxyClient struct {
-	API *httpapi.APIClient
+	API    *httpapi.APIClient
+	Node   ecommon.Provider
+	Policy ecommon.ProviderPolicy
 }
 
 // BlockNumber returns the current block number.
 func (c *ProxyClient) BlockNumber(ctx context.Context) (uint64, error) {
 	var result hexutil.Uint64
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module: ecommon.ProxyModule,
-		Action: "eth_blockNumber",
-		Result: &result,
+	err := c.call(ctx, &proxyCall{
+		action: "eth_blockNumber",
+		result: &result,
 	})
 
 	return uint64(result), err
@@ -48,6 +51,25 @@ type ProxyBaseBlockInfo struct {
 	Timestamp        time.Time   `etherscan:"timestamp,hex"`
 	TransactionsRoot common.Hash `etherscan:"transactionsRoot"`
 	Uncles           []common.Hash
+
+	// Fields added by the London, Shanghai, Cancun and Prague upgrades,
+	// which are absent from earlier blocks.
+	BaseFeePerGas         *big.Int     `etherscan:"baseFeePerGas,hex,omitempty"`
+	WithdrawalsRoot       common.Hash  `etherscan:"withdrawalsRoot,omitempty"`
+	Withdrawals           []Withdrawal `etherscan:"withdrawals,omitempty"`
+	BlobGasUsed           uint64       `etherscan:"blobGasUsed,hex,omitempty"`
+	ExcessBlobGas         uint64       `etherscan:"excessBlobGas,hex,omitempty"`
+	ParentBeaconBlockRoot common.Hash  `etherscan:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash          common.Hash  `etherscan:"requestsHash,omitempty"`
+}
+
+// Withdrawal describes a validator withdrawal from the beacon chain.
+type Withdrawal struct {
+	Index          uint64 `etherscan:"index,hex"`
+	ValidatorIndex uint64 `etherscan:"validatorIndex,hex"`
+	Address        common.Address
+	// Amount is the withdrawn amount in gwei.
+	Amount uint64 `etherscan:"amount,hex"`
 }
 
 // ProxyFullBlockInfo contains the full information on a block and its
@@ -59,7 +81,7 @@ type ProxyFullBlockInfo struct {
 }
 
 type getBlockByNumRequest struct {
-	Number  uint64 `etherscan:"tag,hex"`
+	Tag     ecommon.BlockTag
 	Boolean bool
 }
 
@@ -67,14 +89,23 @@ type getBlockByNumRequest struct {
 func (c *ProxyClient) GetBlockByNumberFull(
 	ctx context.Context, number uint64,
 ) (*ProxyFullBlockInfo, error) {
-	req := getBlockByNumRequest{Number: number, Boolean: true}
+	return c.GetBlockByTagFull(ctx, ecommon.BlockTagNumber(number))
+}
+
+// GetBlockByTagFull returns full information about a block by block number
+// or named tag, such as pending, safe or finalized. Block hashes are not
+// supported.
+func (c *ProxyClient) GetBlockByTagFull(
+	ctx context.Context, tag ecommon.BlockTag,
+) (*ProxyFullBlockInfo, error) {
+	req := getBlockByNumRequest{Tag: tag, Boolean: true}
 	result := new(ProxyFullBlockInfo)
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getBlockByNumber",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getBlockByNumber",
+		request: req,
+		args:    []interface{}{tag, true},
+		result:  result,
 	})
 
 	return result, err
@@ -92,14 +123,23 @@ type ProxySummaryBlockInfo struct {
 func (c *ProxyClient) GetBlockByNumberSummary(
 	ctx context.Context, number uint64,
 ) (*ProxySummaryBlockInfo, error) {
-	req := getBlockByNumRequest{Number: number, Boolean: false}
+	return c.GetBlockByTagSummary(ctx, ecommon.BlockTagNumber(number))
+}
+
+// GetBlockByTagSummary returns summary information about a block by block
+// number or named tag, such as pending, safe or finalized. Block hashes are
+// not supported.
+func (c *ProxyClient) GetBlockByTagSummary(
+	ctx context.Context, tag ecommon.BlockTag,
+) (*ProxySummaryBlockInfo, error) {
+	req := getBlockByNumRequest{Tag: tag, Boolean: false}
 	result := new(ProxySummaryBlockInfo)
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getBlockByNumber",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getBlockByNumber",
+		request: req,
+		args:    []interface{}{tag, false},
+		result:  result,
 	})
 
 	return result, err
@@ -111,10 +151,13 @@ type BlockNumberAndIndex struct {
 	Index  uint32 `etherscan:"index,hex"`
 }
 
+func (req *BlockNumberAndIndex) args() []interface{} {
+	return []interface{}{hexutil.Uint64(req.Number), hexutil.Uint(req.Index)}
+}
+
 // ProxyUncleBlockInfo contains information about an uncle block.
 type ProxyUncleBlockInfo struct {
 	ProxyBaseBlockInfo
-	BaseFeePerGas *big.Int `etherscan:"baseFeePerGas,hex"`
 }
 
 // GetUncleByBlockNumberAndIndex returns information about a uncle by block number.
@@ -123,11 +166,11 @@ func (c *ProxyClient) GetUncleByBlockNumberAndIndex(
 ) (*ProxyUncleBlockInfo, error) {
 	result := new(ProxyUncleBlockInfo)
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getUncleByBlockNumberAndIndex",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getUncleByBlockNumberAndIndex",
+		request: req,
+		args:    req.args(),
+		result:  result,
 	})
 
 	return result, err
@@ -144,11 +187,11 @@ func (c *ProxyClient) GetBlockTransactionCountByNumber(
 	req := blockTxCountRequest{number}
 	var result hexutil.Uint
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getBlockTransactionCountByNumber",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getBlockTransactionCountByNumber",
+		request: req,
+		args:    []interface{}{hexutil.Uint64(number)},
+		result:  &result,
 	})
 
 	return uint32(result), err
@@ -171,6 +214,34 @@ type ProxyTransactionInfo struct {
 	V                uint32   `etherscan:"v,hex"`
 	R                *big.Int `etherscan:"r,hex"`
 	S                *big.Int `etherscan:"s,hex"`
+
+	// Fields of typed transactions, which are absent from legacy
+	// transactions or from transaction types that predate them.
+	ChainID              *big.Int        `etherscan:"chainId,hex,omitempty"`
+	AccessList           []AccessTuple   `etherscan:"accessList,omitempty"`
+	YParity              uint32          `etherscan:"yParity,hex,omitempty"`
+	MaxFeePerGas         *big.Int        `etherscan:"maxFeePerGas,hex,omitempty"`
+	MaxPriorityFeePerGas *big.Int        `etherscan:"maxPriorityFeePerGas,hex,omitempty"`
+	MaxFeePerBlobGas     *big.Int        `etherscan:"maxFeePerBlobGas,hex,omitempty"`
+	BlobVersionedHashes  []common.Hash   `etherscan:"blobVersionedHashes,omitempty"`
+	AuthorizationList    []Authorization `etherscan:"authorizationList,omitempty"`
+}
+
+// AccessTuple is an entry in an EIP-2930 access list.
+type AccessTuple struct {
+	Address     common.Address
+	StorageKeys []common.Hash `etherscan:"storageKeys"`
+}
+
+// Authorization is an entry in the authorization list of an EIP-7702
+// set-code transaction, which delegates the signer's code to Address.
+type Authorization struct {
+	ChainID *big.Int `etherscan:"chainId,hex"`
+	Address common.Address
+	Nonce   uint64   `etherscan:"nonce,hex"`
+	YParity uint32   `etherscan:"yParity,hex"`
+	R       *big.Int `etherscan:"r,hex"`
+	S       *big.Int `etherscan:"s,hex"`
 }
 
 // GetTransactionsByHash returns the information about a transaction requested by transaction hash.
@@ -180,11 +251,11 @@ func (c *ProxyClient) GetTransactionByHash(
 	req := struct{ TxHash common.Hash }{txHash}
 	result := new(ProxyTransactionInfo)
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getTransactionByHash",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getTransactionByHash",
+		request: req,
+		args:    []interface{}{txHash},
+		result:  result,
 	})
 
 	return result, err
@@ -196,11 +267,11 @@ func (c *ProxyClient) GetTransactionByBlockNumberAndIndex(
 	ctx context.Context, req *BlockNumberAndIndex,
 ) (*ProxyTransactionInfo, error) {
 	result := new(ProxyTransactionInfo)
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getTransactionByBlockNumberAndIndex",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getTransactionByBlockNumberAndIndex",
+		request: req,
+		args:    req.args(),
+		result:  result,
 	})
 
 	return result, err
@@ -209,7 +280,7 @@ func (c *ProxyClient) GetTransactionByBlockNumberAndIndex(
 // TxCountRequest contains request parameters for GetTransactionCount.
 type TxCountRequest struct {
 	Address common.Address
-	Tag     ecommon.BlockParameter
+	Tag     ecommon.BlockTag
 }
 
 // GetTransactionCount returns the number of transactions performed by an address.
@@ -217,11 +288,11 @@ func (c *ProxyClient) GetTransactionCount(
 	ctx context.Context, req *TxCountRequest,
 ) (uint64, error) {
 	var result hexutil.Uint64
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getTransactionCount",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getTransactionCount",
+		request: req,
+		args:    []interface{}{req.Address, req.Tag},
+		result:  &result,
 	})
 
 	return uint64(result), err
@@ -232,11 +303,11 @@ func (c *ProxyClient) SendRawTransaction(
 	ctx context.Context, data []byte,
 ) (result common.Hash, err error) {
 	req := struct{ Hex []byte }{data}
-	err = c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_sendRawTransaction",
-		Request: req,
-		Result:  &result,
+	err = c.call(ctx, &proxyCall{
+		action:  "eth_sendRawTransaction",
+		request: req,
+		args:    []interface{}{hexutil.Bytes(data)},
+		result:  &result,
 	})
 
 	return result, err
@@ -258,6 +329,12 @@ type ProxyTransactionReceipt struct {
 	TransactionHash   common.Hash `etherscan:"transactionHash"`
 	TransactionIndex  uint32      `etherscan:"transactionIndex,hex"`
 	Type              uint32      `etherscan:"type,hex"`
+	BlobGasUsed       uint64      `etherscan:"blobGasUsed,hex,omitempty"`
+	BlobGasPrice      *big.Int    `etherscan:"blobGasPrice,hex,omitempty"`
+
+	// Root is the post-transaction state root, which replaces Status in
+	// receipts from before the Byzantium upgrade.
+	Root []byte `etherscan:"root,omitempty"`
 }
 
 // ProxyTxLog describes a transaction log.
@@ -271,8 +348,8 @@ type ProxyTxLog struct {
 	Topics              []common.Hash
 	TransactionHash     common.Hash `etherscan:"transactionHash"`
 	TransactionIndex    uint32      `etherscan:"transactionIndex,hex"`
-	TransactionLogIndex uint32      `etherscan:"transactionLogIndex,hex"`
-	Type                string
+	TransactionLogIndex uint32      `etherscan:"transactionLogIndex,hex,omitempty"`
+	Type                string      `etherscan:"type,omitempty"`
 }
 
 // GetTransactionReceipt returns the receipt of a transaction by transaction hash.
@@ -282,34 +359,94 @@ func (c *ProxyClient) GetTransactionReceipt(
 	req := struct{ TxHash common.Hash }{txHash}
 	result := new(ProxyTransactionReceipt)
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getTransactionReceipt",
-		Request: req,
-		Result:  result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getTransactionReceipt",
+		request: req,
+		args:    []interface{}{txHash},
+		result:  result,
 	})
 
 	return result, err
 }
 
-// CallRequest contains the request parameters for Call.
+// CallRequest contains the request parameters for Call. Unset optional
+// fields are omitted, and a zero To address denotes contract creation.
+// GasPrice must not be combined with the EIP-1559 fee fields.
 type CallRequest struct {
-	To   common.Address
-	Data []byte
-	Tag  ecommon.BlockParameter
+	From                 *common.Address `etherscan:"from,omitempty"`
+	To                   common.Address  `etherscan:"to,omitempty"`
+	Gas                  *big.Int        `etherscan:"gas,hex,omitempty"`
+	GasPrice             *big.Int        `etherscan:"gasPrice,hex,omitempty"`
+	MaxFeePerGas         *big.Int        `etherscan:"maxFeePerGas,hex,omitempty"`
+	MaxPriorityFeePerGas *big.Int        `etherscan:"maxPriorityFeePerGas,hex,omitempty"`
+	Value                *big.Int        `etherscan:"value,hex,omitempty"`
+	Data                 []byte          `etherscan:"data,omitempty"`
+	Tag                  ecommon.BlockTag
+}
+
+func (req *CallRequest) msg() *callMsg {
+	return &callMsg{
+		From:                 req.From,
+		To:                   optionalAddress(req.To),
+		Gas:                  (*hexutil.Big)(req.Gas),
+		GasPrice:             (*hexutil.Big)(req.GasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(req.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(req.MaxPriorityFeePerGas),
+		Value:                (*hexutil.Big)(req.Value),
+		Data:                 req.Data,
+	}
+}
+
+// callMsg is the JSON-RPC encoding of a message call.
+type callMsg struct {
+	From                 *common.Address `json:"from,omitempty"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  *hexutil.Big    `json:"gas,omitempty"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value,omitempty"`
+	Data                 hexutil.Bytes   `json:"data,omitempty"`
+}
+
+// optionalAddress returns nil for the zero address, which as the recipient
+// of a message call denotes contract creation.
+func optionalAddress(addr common.Address) *common.Address {
+	if addr == (common.Address{}) {
+		return nil
+	}
+
+	return &addr
+}
+
+// ErrConflictingFees is returned when a message call sets both a legacy gas
+// price and EIP-1559 fee fields.
+var ErrConflictingFees = errors.New("gasPrice cannot be combined with maxFeePerGas or maxPriorityFeePerGas")
+
+func (msg *callMsg) validate() error {
+	if msg.GasPrice != nil && (msg.MaxFeePerGas != nil || msg.MaxPriorityFeePerGas != nil) {
+		return ErrConflictingFees
+	}
+
+	return nil
 }
 
 // Call executes a new message call immediately without creating a transaction on the block chain.
 func (c *ProxyClient) Call(
 	ctx context.Context, req *CallRequest,
 ) ([]byte, error) {
+	msg := req.msg()
+	if err := msg.validate(); err != nil {
+		return nil, err
+	}
+
 	var result hexutil.Bytes
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_call",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_call",
+		request: req,
+		args:    []interface{}{msg, req.Tag},
+		result:  &result,
 	})
 
 	return result, err
@@ -318,7 +455,7 @@ func (c *ProxyClient) Call(
 // GetCodeRequest contains the request parameters for GetCode.
 type GetCodeRequest struct {
 	Address common.Address
-	Tag     ecommon.BlockParameter
+	Tag     ecommon.BlockTag
 }
 
 // GetCode returns code at a given address.
@@ -326,11 +463,11 @@ func (c *ProxyClient) GetCode(
 	ctx context.Context, req *GetCodeRequest,
 ) ([]byte, error) {
 	var result hexutil.Bytes
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getCode",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getCode",
+		request: req,
+		args:    []interface{}{req.Address, req.Tag},
+		result:  &result,
 	})
 
 	return result, err
@@ -339,8 +476,8 @@ func (c *ProxyClient) GetCode(
 // GetStorageRequest contains the request parameters for GetStorageAt.
 type GetStorageRequest struct {
 	Address  common.Address
-	Position uint32 `etherscan:"position,hex"`
-	Tag      ecommon.BlockParameter
+	Position common.Hash `etherscan:"position,hex"`
+	Tag      ecommon.BlockTag
 }
 
 // GetStorageAt returns the value from a storage position at a given address.
@@ -348,11 +485,11 @@ func (c *ProxyClient) GetStorageAt(
 	ctx context.Context, req *GetStorageRequest,
 ) ([]byte, error) {
 	var result hexutil.Bytes
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_getStorageAt",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_getStorageAt",
+		request: req,
+		args:    []interface{}{req.Address, req.Position, req.Tag},
+		result:  &result,
 	})
 
 	return result, err
@@ -361,10 +498,9 @@ func (c *ProxyClient) GetStorageAt(
 // GasPrice returns the current price per gas in wei.
 func (c *ProxyClient) GasPrice(ctx context.Context) (*big.Int, error) {
 	var result hexutil.Big
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module: ecommon.ProxyModule,
-		Action: "eth_gasPrice",
-		Result: &result,
+	err := c.call(ctx, &proxyCall{
+		action: "eth_gasPrice",
+		result: &result,
 	})
 	if err != nil {
 		return nil, err
@@ -374,25 +510,48 @@ func (c *ProxyClient) GasPrice(ctx context.Context) (*big.Int, error) {
 }
 
 // EstimateGasRequest contains the request parameters for EstimateGas.
+// Unset optional fields are omitted, and a zero To address denotes contract
+// creation. GasPrice must not be combined with the EIP-1559 fee fields.
 type EstimateGasRequest struct {
-	Data     []byte
-	To       common.Address
-	Value    *big.Int `etherscan:"value,hex"`
-	Gas      *big.Int `etherscan:"gas,hex"`
-	GasPrice *big.Int `etherscan:"gasPrice,hex"`
+	From                 *common.Address `etherscan:"from,omitempty"`
+	To                   common.Address  `etherscan:"to,omitempty"`
+	Gas                  *big.Int        `etherscan:"gas,hex,omitempty"`
+	GasPrice             *big.Int        `etherscan:"gasPrice,hex,omitempty"`
+	MaxFeePerGas         *big.Int        `etherscan:"maxFeePerGas,hex,omitempty"`
+	MaxPriorityFeePerGas *big.Int        `etherscan:"maxPriorityFeePerGas,hex,omitempty"`
+	Value                *big.Int        `etherscan:"value,hex,omitempty"`
+	Data                 []byte          `etherscan:"data,omitempty"`
+}
+
+func (req *EstimateGasRequest) msg() *callMsg {
+	return &callMsg{
+		From:                 req.From,
+		To:                   optionalAddress(req.To),
+		Gas:                  (*hexutil.Big)(req.Gas),
+		GasPrice:             (*hexutil.Big)(req.GasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(req.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(req.MaxPriorityFeePerGas),
+		Value:                (*hexutil.Big)(req.Value),
+		Data:                 req.Data,
+	}
 }
 
 // EstimateGas makes a call or transaction, which won't be added to the blockchain and returns the used gas.
 func (c *ProxyClient) EstimateGas(
 	ctx context.Context, req *EstimateGasRequest,
 ) (*big.Int, error) {
+	msg := req.msg()
+	if err := msg.validate(); err != nil {
+		return nil, err
+	}
+
 	var result hexutil.Big
 
-	err := c.API.Call(ctx, &httpapi.CallParams{
-		Module:  ecommon.ProxyModule,
-		Action:  "eth_estimateGas",
-		Request: req,
-		Result:  &result,
+	err := c.call(ctx, &proxyCall{
+		action:  "eth_estimateGas",
+		request: req,
+		args:    []interface{}{msg},
+		result:  &result,
 	})
 	if err != nil {
 		return nil, err
@@ -2,20 +2,36 @@ package proxy_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ryanc414/etherscan-api-go"
 	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/httpapi"
 	"github.com/ryanc414/etherscan-api-go/proxy"
 	"github.com/ryanc414/etherscan-api-go/testbed"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// typedTxHashes are transactions of types 0 to 3, in order, in block 19500000.
+var typedTxHashes = []string{
+	"0xb29c8ecd1dfed9e8f37ee82cd26d9889393fb9107aec277a32c94af6a5f600d6",
+	"0x03a35e65d7f6408ebf60f3f20ff530643743c3bf18ffed5151306856fdf3bef6",
+	"0x34298606ddeb907124f9e839f57632f2f1ec27db6df8e1a6b20d9ac7c03f55f2",
+	"0x79fa7a88246b307b0d916a7c9428ad4f7f23cdd33d5fd76037b1b368a7d35f03",
+}
+
 func TestProxy(t *testing.T) {
 	m := testbed.NewMockServer("proxy", true)
 	t.Cleanup(m.Close)
@@ -42,6 +58,12 @@ func TestProxy(t *testing.T) {
 		cupaloy.SnapshotT(t, block)
 	})
 
+	t.Run("GetBlockByNumberFullCancun", func(t *testing.T) {
+		block, err := client.Proxy.GetBlockByNumberFull(ctx, 19500000)
+		require.NoError(t, err)
+		cupaloy.SnapshotT(t, block)
+	})
+
 	t.Run("GetBlockByNumberSummary", func(t *testing.T) {
 		block, err := client.Proxy.GetBlockByNumberSummary(ctx, 68943)
 		require.NoError(t, err)
@@ -72,6 +94,22 @@ func TestProxy(t *testing.T) {
 		cupaloy.SnapshotT(t, txInfo)
 	})
 
+	t.Run("GetTypedTransactions", func(t *testing.T) {
+		for i, hash := range typedTxHashes {
+			t.Run(fmt.Sprintf("Type%d", i), func(t *testing.T) {
+				txInfo, err := client.Proxy.GetTransactionByHash(ctx, common.HexToHash(hash))
+				require.NoError(t, err)
+				assert.Equal(t, uint32(i), txInfo.Type)
+
+				receipt, err := client.Proxy.GetTransactionReceipt(ctx, common.HexToHash(hash))
+				require.NoError(t, err)
+				assert.Equal(t, uint32(i), receipt.Type)
+
+				cupaloy.SnapshotT(t, txInfo, receipt)
+			})
+		}
+	})
+
 	t.Run("GetTxByBlockNumberAndIndex", func(t *testing.T) {
 		txInfo, err := client.Proxy.GetTransactionByBlockNumberAndIndex(ctx, &proxy.BlockNumberAndIndex{
 			Number: 12989213,
@@ -85,7 +123,7 @@ func TestProxy(t *testing.T) {
 	t.Run("GetTransactionCount", func(t *testing.T) {
 		count, err := client.Proxy.GetTransactionCount(ctx, &proxy.TxCountRequest{
 			Address: common.HexToAddress("0x4bd5900Cb274ef15b153066D736bf3e83A9ba44e"),
-			Tag:     ecommon.BlockParameterLatest,
+			Tag:     ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
 		})
 		require.NoError(t, err)
 
@@ -112,7 +150,7 @@ func TestProxy(t *testing.T) {
 		result, err := client.Proxy.Call(ctx, &proxy.CallRequest{
 			To:   common.HexToAddress("0xAEEF46DB4855E25702F8237E8f403FddcaF931C0"),
 			Data: hexutil.MustDecode("0x70a08231000000000000000000000000e16359506c028e51f16be38986ec5746251e9724"),
-			Tag:  ecommon.BlockParameterLatest,
+			Tag:  ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
 		})
 		require.NoError(t, err)
 
@@ -120,10 +158,34 @@ func TestProxy(t *testing.T) {
 		assert.Equal(t, expectedResult, result)
 	})
 
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	sender := common.HexToAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae")
+
+	t.Run("CallFromWithValue", func(t *testing.T) {
+		result, err := client.Proxy.Call(ctx, &proxy.CallRequest{
+			From:  &sender,
+			To:    weth,
+			Value: big.NewInt(1e18),
+			Data:  hexutil.MustDecode("0xd0e30db0"),
+			Tag:   ecommon.BlockTagNumber(1000000),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("CallConflictingFees", func(t *testing.T) {
+		_, err := client.Proxy.Call(ctx, &proxy.CallRequest{
+			To:           weth,
+			GasPrice:     big.NewInt(30000000000),
+			MaxFeePerGas: big.NewInt(30000000000),
+		})
+		assert.ErrorIs(t, err, proxy.ErrConflictingFees)
+	})
+
 	t.Run("GetCode", func(t *testing.T) {
 		result, err := client.Proxy.GetCode(ctx, &proxy.GetCodeRequest{
 			Address: common.HexToAddress("0xf75e354c5edc8efed9b59ee9f67a80845ade7d0c"),
-			Tag:     ecommon.BlockParameterLatest,
+			Tag:     ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
 		})
 		require.NoError(t, err)
 
@@ -131,11 +193,28 @@ func TestProxy(t *testing.T) {
 		assert.Equal(t, expectedResult, result)
 	})
 
+	t.Run("GetCodeAtBlock", func(t *testing.T) {
+		result, err := client.Proxy.GetCode(ctx, &proxy.GetCodeRequest{
+			Address: common.HexToAddress("0xf75e354c5edc8efed9b59ee9f67a80845ade7d0c"),
+			Tag:     ecommon.BlockTagNumber(1000000),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("GetCodeAtBlockHash", func(t *testing.T) {
+		_, err := client.Proxy.GetCode(ctx, &proxy.GetCodeRequest{
+			Address: common.HexToAddress("0xf75e354c5edc8efed9b59ee9f67a80845ade7d0c"),
+			Tag:     ecommon.BlockTagHash(common.HexToHash("0x6557ff2c1746ee6df9af644ac874e23a4ce5a39e4885d9a11b3107da8bd911f6")),
+		})
+		assert.ErrorIs(t, err, ecommon.ErrHashTagUnsupported)
+	})
+
 	t.Run("GetStorageAt", func(t *testing.T) {
 		result, err := client.Proxy.GetStorageAt(ctx, &proxy.GetStorageRequest{
 			Address:  common.HexToAddress("0x6e03d9cce9d60f3e9f2597e13cd4c54c55330cfd"),
-			Position: 0,
-			Tag:      ecommon.BlockParameterLatest,
+			Position: common.Hash{},
+			Tag:      ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
 		})
 		require.NoError(t, err)
 
@@ -164,4 +243,220 @@ func TestProxy(t *testing.T) {
 		expectedGas := big.NewInt(25942)
 		require.Equal(t, 0, gas.Cmp(expectedGas))
 	})
+
+	t.Run("EstimateGasDynamicFee", func(t *testing.T) {
+		gas, err := client.Proxy.EstimateGas(ctx, &proxy.EstimateGasRequest{
+			From:                 &sender,
+			To:                   weth,
+			Value:                big.NewInt(1e18),
+			MaxFeePerGas:         big.NewInt(30000000000),
+			MaxPriorityFeePerGas: big.NewInt(2000000000),
+			Data:                 hexutil.MustDecode("0xd0e30db0"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, gas.Cmp(big.NewInt(45024)))
+	})
+}
+
+func TestProxyNode(t *testing.T) {
+	m := testbed.NewMockServer("proxy", true)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	node := testbed.NewMockNode()
+	t.Cleanup(node.Close)
+
+	rpcClient, err := rpc.DialHTTP(node.URL())
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:         m.APIKey,
+		BaseURL:        u,
+		Node:           rpcClient,
+		ProviderPolicy: ecommon.ProviderPolicyNodeOnly,
+	})
+
+	ctx := context.Background()
+
+	node.Handle("eth_blockNumber", func(params []json.RawMessage) (interface{}, error) {
+		return hexutil.Uint64(12806954), nil
+	})
+
+	blockResult, err := m.FixtureResult("eth_getBlockByNumber", "boolean=true&tag=0x10d4f")
+	require.NoError(t, err)
+	node.Handle("eth_getBlockByNumber", func(params []json.RawMessage) (interface{}, error) {
+		if len(params) != 2 || string(params[0]) != `"0x10d4f"` || string(params[1]) != "true" {
+			return nil, fmt.Errorf("unexpected params %s", params)
+		}
+
+		return blockResult, nil
+	})
+
+	node.Handle("eth_call", func(params []json.RawMessage) (interface{}, error) {
+		var msg struct {
+			To   common.Address
+			Data hexutil.Bytes
+		}
+		if err := json.Unmarshal(params[0], &msg); err != nil {
+			return nil, err
+		}
+
+		if msg.To != common.HexToAddress("0xAEEF46DB4855E25702F8237E8f403FddcaF931C0") ||
+			string(params[1]) != `"latest"` {
+			return nil, fmt.Errorf("unexpected params %s", params)
+		}
+
+		return hexutil.Bytes(msg.Data[:4]), nil
+	})
+
+	node.Handle("eth_estimateGas", func(params []json.RawMessage) (interface{}, error) {
+		expected := `{"from":"0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",` +
+			`"to":"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",` +
+			`"maxFeePerGas":"0x6fc23ac00","value":"0xde0b6b3a7640000"}`
+		if len(params) != 1 || string(params[0]) != expected {
+			return nil, fmt.Errorf("unexpected params %s", params)
+		}
+
+		return hexutil.Uint64(45024), nil
+	})
+
+	node.Handle("eth_gasPrice", func(params []json.RawMessage) (interface{}, error) {
+		return nil, &testbed.NodeError{Code: -32005, Message: "node unavailable: limit exceeded"}
+	})
+
+	node.Handle("eth_getCode", func(params []json.RawMessage) (interface{}, error) {
+		return nil, errors.New("invalid argument 1: hex string without 0x prefix")
+	})
+
+	node.Handle("eth_getStorageAt", func(params []json.RawMessage) (interface{}, error) {
+		expected := `{"blockHash":"0x6557ff2c1746ee6df9af644ac874e23a4ce5a39e4885d9a11b3107da8bd911f6"}`
+		if len(params) != 3 || string(params[2]) != expected {
+			return nil, fmt.Errorf("unexpected params %s", params)
+		}
+
+		return hexutil.Bytes(common.Hash{31: 1}.Bytes()), nil
+	})
+
+	t.Run("BlockNumber", func(t *testing.T) {
+		num, err := client.Proxy.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12806954), num)
+	})
+
+	t.Run("Meta", func(t *testing.T) {
+		var meta httpapi.ResponseMeta
+		metaCtx := httpapi.WithCallOptions(ctx, httpapi.WithMeta(&meta))
+
+		_, err := client.Proxy.BlockNumber(metaCtx)
+		require.NoError(t, err)
+
+		assert.True(t, meta.Node)
+		assert.Equal(t, 1, meta.Attempts)
+		assert.Equal(t, `"0xc36b2a"`, string(meta.Result))
+	})
+
+	t.Run("GetBlockByNumberFull", func(t *testing.T) {
+		block, err := client.Proxy.GetBlockByNumberFull(ctx, 68943)
+		require.NoError(t, err)
+
+		etherscanClient := proxy.ProxyClient{API: client.Proxy.API}
+		expected, err := etherscanClient.GetBlockByNumberFull(ctx, 68943)
+		require.NoError(t, err)
+		assert.Equal(t, expected, block)
+	})
+
+	t.Run("Call", func(t *testing.T) {
+		result, err := client.Proxy.Call(ctx, &proxy.CallRequest{
+			To:   common.HexToAddress("0xAEEF46DB4855E25702F8237E8f403FddcaF931C0"),
+			Data: hexutil.MustDecode("0x70a08231000000000000000000000000e16359506c028e51f16be38986ec5746251e9724"),
+			Tag:  ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, hexutil.MustDecode("0x70a08231"), result)
+	})
+
+	t.Run("EstimateGas", func(t *testing.T) {
+		sender := common.HexToAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae")
+		gas, err := client.Proxy.EstimateGas(ctx, &proxy.EstimateGasRequest{
+			From:         &sender,
+			To:           common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+			Value:        big.NewInt(1e18),
+			MaxFeePerGas: big.NewInt(30000000000),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, gas.Cmp(big.NewInt(45024)))
+	})
+
+	t.Run("NodeFailure", func(t *testing.T) {
+		_, err := client.Proxy.GasPrice(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "node unavailable")
+	})
+
+	t.Run("NodeFirst", func(t *testing.T) {
+		fallbackClient := client.Proxy
+		fallbackClient.Policy = ecommon.ProviderPolicyNodeFirst
+
+		price, err := fallbackClient.GasPrice(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, big.NewInt(18000000000).Cmp(price))
+
+		// Errors about the call itself are not retried against Etherscan.
+		_, err = fallbackClient.GetCode(ctx, &proxy.GetCodeRequest{
+			Address: common.HexToAddress("0xf75e354c5edc8efed9b59ee9f67a80845ade7d0c"),
+			Tag:     ecommon.BlockTagNamed(ecommon.BlockParameterLatest),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "invalid argument 1: hex string without 0x prefix", err.Error())
+	})
+
+	t.Run("EtherscanFirst", func(t *testing.T) {
+		fallbackClient := client.Proxy
+		fallbackClient.Policy = ecommon.ProviderPolicyEtherscanFirst
+
+		num, err := fallbackClient.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12806953), num)
+
+		// An invalid API key is not a reason to fall back.
+		badKeyCtx := httpapi.WithCallOptions(ctx, httpapi.WithAPIKey("invalid"))
+		calls := node.Calls("eth_blockNumber")
+
+		_, err = fallbackClient.BlockNumber(badKeyCtx)
+		require.Error(t, err)
+		assert.Equal(t, calls, node.Calls("eth_blockNumber"))
+
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(down.Close)
+
+		downURL, err := url.Parse(down.URL)
+		require.NoError(t, err)
+
+		downClient := etherscan.New(&etherscan.Params{
+			APIKey:         m.APIKey,
+			BaseURL:        downURL,
+			Node:           rpcClient,
+			ProviderPolicy: ecommon.ProviderPolicyEtherscanFirst,
+		})
+
+		num, err = downClient.Proxy.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12806954), num)
+		assert.Equal(t, calls+1, node.Calls("eth_blockNumber"))
+
+		// Etherscan does not accept block hashes, so only the node can
+		// serve calls at a block hash.
+		value, err := fallbackClient.GetStorageAt(ctx, &proxy.GetStorageRequest{
+			Address:  common.HexToAddress("0x6e03d9cce9d60f3e9f2597e13cd4c54c55330cfd"),
+			Position: common.Hash{},
+			Tag:      ecommon.BlockTagHash(common.HexToHash("0x6557ff2c1746ee6df9af644ac874e23a4ce5a39e4885d9a11b3107da8bd911f6")),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, common.Hash{31: 1}.Bytes(), value)
+	})
 }
@@ -0,0 +1,580 @@
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+type handlerFunc func(ctx context.Context, params []json.RawMessage) (interface{}, error)
+
+// method describes how a JSON-RPC method is served.
+type method struct {
+	handle handlerFunc
+
+	// upstream is set for methods that call Etherscan, which are subject to
+	// the rate limit.
+	upstream bool
+
+	// cacheable is set for methods whose results may be cached.
+	cacheable bool
+}
+
+func (s *Server) registerMethods() map[string]method {
+	cached := func(handle handlerFunc) method {
+		return method{handle: handle, upstream: true, cacheable: true}
+	}
+
+	// Nonces, gas prices and gas estimates change with the pending state,
+	// such as after a transaction is sent, so they are never cached.
+	uncached := func(handle handlerFunc) method {
+		return method{handle: handle, upstream: true}
+	}
+
+	return map[string]method{
+		"eth_chainId": {handle: s.ethChainID},
+		"net_version": {handle: s.netVersion},
+
+		"eth_blockNumber":                         cached(s.blockNumber),
+		"eth_gasPrice":                            uncached(s.gasPrice),
+		"eth_getBalance":                          cached(s.getBalance),
+		"eth_getCode":                             cached(s.getCode),
+		"eth_getStorageAt":                        cached(s.getStorageAt),
+		"eth_getTransactionCount":                 uncached(s.getTransactionCount),
+		"eth_call":                                cached(s.ethCall),
+		"eth_estimateGas":                         uncached(s.estimateGas),
+		"eth_getBlockByNumber":                    cached(s.getBlockByNumber),
+		"eth_getBlockTransactionCountByNumber":    cached(s.getBlockTransactionCountByNumber),
+		"eth_getTransactionByHash":                cached(s.getTransactionByHash),
+		"eth_getTransactionByBlockNumberAndIndex": cached(s.getTransactionByBlockNumberAndIndex),
+		"eth_getTransactionReceipt":               cached(s.getTransactionReceipt),
+		"eth_getLogs":                             cached(s.getLogs),
+
+		"eth_sendRawTransaction": uncached(s.sendRawTransaction),
+	}
+}
+
+// decodeParams decodes positional params into values, of which the first
+// are required and the remainder optional. Optional values are left
+// unchanged if omitted or null.
+func decodeParams(params []json.RawMessage, required int, values ...interface{}) error {
+	if len(params) > len(values) {
+		return invalidParams("too many arguments, want at most %d", len(values))
+	}
+
+	if len(params) < required {
+		return invalidParams("missing value for required argument %d", len(params))
+	}
+
+	for i := range params {
+		if i >= required && string(params[i]) == "null" {
+			continue
+		}
+
+		if err := json.Unmarshal(params[i], values[i]); err != nil {
+			return invalidParams("invalid argument %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) ethChainID(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	if err := decodeParams(params, 0); err != nil {
+		return nil, err
+	}
+
+	return hexutil.Uint64(s.chainID), nil
+}
+
+func (s *Server) netVersion(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	if err := decodeParams(params, 0); err != nil {
+		return nil, err
+	}
+
+	return strconv.FormatUint(s.chainID, 10), nil
+}
+
+func (s *Server) blockNumber(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	if err := decodeParams(params, 0); err != nil {
+		return nil, err
+	}
+
+	number, err := s.proxy.BlockNumber(ctx)
+	return hexutil.Uint64(number), err
+}
+
+func (s *Server) gasPrice(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	if err := decodeParams(params, 0); err != nil {
+		return nil, err
+	}
+
+	price, err := s.proxy.GasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+func (s *Server) getBalance(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		address common.Address
+		tag     ecommon.BlockTag
+	)
+
+	if err := decodeParams(params, 1, &address, &tag); err != nil {
+		return nil, err
+	}
+
+	if _, ok := tag.Hash(); ok {
+		return nil, invalidParams("balances cannot be queried by block hash")
+	}
+
+	// The balance action only accepts the latest, earliest and pending
+	// blocks, so balances at other blocks are read from balancehistory.
+	if param, ok := tag.Param(); ok {
+		switch param {
+		case ecommon.BlockParameterLatest, ecommon.BlockParameterEarliest, ecommon.BlockParameterPending:
+			balance, err := s.accounts.GetETHBalance(ctx, &accounts.ETHBalanceRequest{
+				Address: address,
+				Tag:     param,
+			})
+
+			return (*hexutil.Big)(balance), err
+		}
+	}
+
+	number, err := s.resolveBlockNumber(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.accounts.GetHistoricalETHBalance(ctx, &accounts.HistoricalETHRequest{
+		Address:     address,
+		BlockNumber: number,
+	})
+
+	return (*hexutil.Big)(balance), err
+}
+
+func (s *Server) getCode(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var req proxy.GetCodeRequest
+	if err := decodeParams(params, 1, &req.Address, &req.Tag); err != nil {
+		return nil, err
+	}
+
+	code, err := s.proxy.GetCode(ctx, &req)
+	return hexutil.Bytes(code), err
+}
+
+func (s *Server) getStorageAt(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		req      proxy.GetStorageRequest
+		position string
+	)
+
+	if err := decodeParams(params, 2, &req.Address, &position, &req.Tag); err != nil {
+		return nil, err
+	}
+
+	slot, ok := new(big.Int).SetString(strings.TrimPrefix(position, "0x"), 16)
+	if !ok || !strings.HasPrefix(position, "0x") || slot.BitLen() > 256 {
+		return nil, invalidParams("invalid storage position %s", position)
+	}
+
+	req.Position = common.BigToHash(slot)
+
+	value, err := s.proxy.GetStorageAt(ctx, &req)
+	return hexutil.Bytes(value), err
+}
+
+func (s *Server) getTransactionCount(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var req proxy.TxCountRequest
+	if err := decodeParams(params, 1, &req.Address, &req.Tag); err != nil {
+		return nil, err
+	}
+
+	count, err := s.proxy.GetTransactionCount(ctx, &req)
+	return hexutil.Uint64(count), err
+}
+
+// callArgs are the arguments of a message call. The call data may be given
+// as either input or data.
+type callArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Big    `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+}
+
+func (args *callArgs) data() ([]byte, error) {
+	if args.Input != nil && args.Data != nil && string(*args.Input) != string(*args.Data) {
+		return nil, invalidParams("both input and data are set and they are not equal")
+	}
+
+	if args.Input != nil {
+		return *args.Input, nil
+	}
+
+	if args.Data != nil {
+		return *args.Data, nil
+	}
+
+	return nil, nil
+}
+
+// from returns the sender, omitting the zero address which is the default.
+func (args *callArgs) from() *common.Address {
+	if args.From == nil || *args.From == (common.Address{}) {
+		return nil
+	}
+
+	return args.From
+}
+
+func (args *callArgs) to() common.Address {
+	if args.To == nil {
+		return common.Address{}
+	}
+
+	return *args.To
+}
+
+func (s *Server) ethCall(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		args callArgs
+		tag  ecommon.BlockTag
+	)
+
+	if err := decodeParams(params, 1, &args, &tag); err != nil {
+		return nil, err
+	}
+
+	data, err := args.data()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.proxy.Call(ctx, &proxy.CallRequest{
+		From:                 args.from(),
+		To:                   args.to(),
+		Gas:                  args.Gas.ToInt(),
+		GasPrice:             args.GasPrice.ToInt(),
+		MaxFeePerGas:         args.MaxFeePerGas.ToInt(),
+		MaxPriorityFeePerGas: args.MaxPriorityFeePerGas.ToInt(),
+		Value:                args.Value.ToInt(),
+		Data:                 data,
+		Tag:                  tag,
+	})
+
+	return hexutil.Bytes(result), err
+}
+
+// estimateGas serves eth_estimateGas. The proxy module always estimates
+// against the latest block, so any block parameter is ignored.
+func (s *Server) estimateGas(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		args callArgs
+		tag  ecommon.BlockTag
+	)
+
+	if err := decodeParams(params, 1, &args, &tag); err != nil {
+		return nil, err
+	}
+
+	data, err := args.data()
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := s.proxy.EstimateGas(ctx, &proxy.EstimateGasRequest{
+		From:                 args.from(),
+		To:                   args.to(),
+		Gas:                  args.Gas.ToInt(),
+		GasPrice:             args.GasPrice.ToInt(),
+		MaxFeePerGas:         args.MaxFeePerGas.ToInt(),
+		MaxPriorityFeePerGas: args.MaxPriorityFeePerGas.ToInt(),
+		Value:                args.Value.ToInt(),
+		Data:                 data,
+	})
+
+	return (*hexutil.Big)(gas), err
+}
+
+// resolveBlockNumber returns the number of the block identified by tag, as
+// the proxy module fetches most block data by number only.
+func (s *Server) resolveBlockNumber(ctx context.Context, tag ecommon.BlockTag) (uint64, error) {
+	if number, ok := tag.Number(); ok {
+		return number, nil
+	}
+
+	if _, ok := tag.Hash(); ok {
+		return 0, invalidParams("blocks cannot be fetched by hash")
+	}
+
+	param, _ := tag.Param()
+
+	switch param {
+	case ecommon.BlockParameterLatest:
+		return s.proxy.BlockNumber(ctx)
+
+	case ecommon.BlockParameterEarliest:
+		return 0, nil
+
+	default:
+		block, err := s.proxy.GetBlockByTagSummary(ctx, tag)
+		if err != nil {
+			return 0, err
+		}
+
+		if block.Hash == (common.Hash{}) {
+			return 0, errors.Errorf("the %s block was not found", param)
+		}
+
+		return block.Number, nil
+	}
+}
+
+// blockTag returns the tag with which to fetch a block. Blocks identified by
+// the pending, safe and finalized tags are fetched by tag, which avoids an
+// extra call and, for the pending block, is the only way to fetch it.
+func (s *Server) blockTag(ctx context.Context, tag ecommon.BlockTag) (ecommon.BlockTag, error) {
+	if param, ok := tag.Param(); ok {
+		switch param {
+		case ecommon.BlockParameterPending, ecommon.BlockParameterSafe, ecommon.BlockParameterFinalized:
+			return tag, nil
+		}
+	}
+
+	number, err := s.resolveBlockNumber(ctx, tag)
+	if err != nil {
+		return ecommon.BlockTag{}, err
+	}
+
+	return ecommon.BlockTagNumber(number), nil
+}
+
+func (s *Server) getBlockByNumber(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		tag  ecommon.BlockTag
+		full bool
+	)
+
+	if err := decodeParams(params, 2, &tag, &full); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.blockTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if !full {
+		block, err := s.proxy.GetBlockByTagSummary(ctx, tag)
+		if err != nil || block.Hash == (common.Hash{}) {
+			return nil, err
+		}
+
+		return newRPCBlock(&block.ProxyBaseBlockInfo, block.TotalDifficulty, block.Transactions), nil
+	}
+
+	block, err := s.proxy.GetBlockByTagFull(ctx, tag)
+	if err != nil || block.Hash == (common.Hash{}) {
+		return nil, err
+	}
+
+	txs := make([]*rpcTransaction, len(block.Transactions))
+	for i := range block.Transactions {
+		txs[i] = newRPCTransaction(&block.Transactions[i])
+	}
+
+	return newRPCBlock(&block.ProxyBaseBlockInfo, block.TotalDifficulty, txs), nil
+}
+
+func (s *Server) getBlockTransactionCountByNumber(
+	ctx context.Context, params []json.RawMessage,
+) (interface{}, error) {
+	var tag ecommon.BlockTag
+	if err := decodeParams(params, 1, &tag); err != nil {
+		return nil, err
+	}
+
+	number, err := s.resolveBlockNumber(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.proxy.GetBlockTransactionCountByNumber(ctx, number)
+	return hexutil.Uint(count), err
+}
+
+func (s *Server) getTransactionByHash(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := decodeParams(params, 1, &hash); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.proxy.GetTransactionByHash(ctx, hash)
+	if err != nil || tx.Hash == (common.Hash{}) {
+		return nil, err
+	}
+
+	return newRPCTransaction(tx), nil
+}
+
+func (s *Server) getTransactionByBlockNumberAndIndex(
+	ctx context.Context, params []json.RawMessage,
+) (interface{}, error) {
+	var (
+		tag   ecommon.BlockTag
+		index hexutil.Uint
+	)
+
+	if err := decodeParams(params, 2, &tag, &index); err != nil {
+		return nil, err
+	}
+
+	number, err := s.resolveBlockNumber(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.proxy.GetTransactionByBlockNumberAndIndex(ctx, &proxy.BlockNumberAndIndex{
+		Number: number,
+		Index:  uint32(index),
+	})
+	if err != nil || tx.Hash == (common.Hash{}) {
+		return nil, err
+	}
+
+	return newRPCTransaction(tx), nil
+}
+
+func (s *Server) getTransactionReceipt(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := decodeParams(params, 1, &hash); err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.proxy.GetTransactionReceipt(ctx, hash)
+	if err != nil || receipt.TransactionHash == (common.Hash{}) {
+		return nil, err
+	}
+
+	return newRPCReceipt(receipt), nil
+}
+
+// filterArgs are the arguments of eth_getLogs.
+type filterArgs struct {
+	BlockHash *common.Hash     `json:"blockHash"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	Addresses addressList      `json:"address"`
+	Topics    []topicList      `json:"topics"`
+}
+
+// addressList is a single address or a list of addresses.
+type addressList []common.Address
+
+func (l *addressList) UnmarshalJSON(data []byte) error {
+	var single common.Address
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = addressList{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]common.Address)(l))
+}
+
+// topicList is a single topic or a list of alternative topics. A null
+// topic matches anything.
+type topicList []common.Hash
+
+func (l *topicList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*l = nil
+		return nil
+	}
+
+	var single common.Hash
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = topicList{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]common.Hash)(l))
+}
+
+func (args *filterArgs) query() ethereum.FilterQuery {
+	q := ethereum.FilterQuery{
+		BlockHash: args.BlockHash,
+		FromBlock: blockNumberArg(args.FromBlock),
+		ToBlock:   blockNumberArg(args.ToBlock),
+		Addresses: args.Addresses,
+		Topics:    make([][]common.Hash, len(args.Topics)),
+	}
+
+	for i := range args.Topics {
+		q.Topics[i] = args.Topics[i]
+	}
+
+	return q
+}
+
+// blockNumberArg converts a block number to the form used by go-ethereum
+// filter queries. An omitted block defaults to the latest block, as in
+// eth_getLogs, whereas a nil number in a filter query denotes block 0.
+// Earliest is block 0 and the other named blocks are negative, which are
+// treated as the latest block.
+func blockNumberArg(number *rpc.BlockNumber) *big.Int {
+	if number == nil {
+		return big.NewInt(int64(rpc.LatestBlockNumber))
+	}
+
+	if *number == rpc.EarliestBlockNumber {
+		return big.NewInt(0)
+	}
+
+	return big.NewInt(number.Int64())
+}
+
+func (s *Server) getLogs(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var args filterArgs
+	if err := decodeParams(params, 1, &args); err != nil {
+		return nil, err
+	}
+
+	logs, err := s.backend.FilterLogs(ctx, args.query())
+	if err != nil {
+		return nil, err
+	}
+
+	if logs == nil {
+		logs = []types.Log{}
+	}
+
+	return logs, nil
+}
+
+func (s *Server) sendRawTransaction(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var data hexutil.Bytes
+	if err := decodeParams(params, 1, &data); err != nil {
+		return nil, err
+	}
+
+	return s.proxy.SendRawTransaction(ctx, data)
+}
@@ -0,0 +1,309 @@
+package rpcserver_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ryanc414/etherscan-api-go"
+	"github.com/ryanc414/etherscan-api-go/rpcserver"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	token     = common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	holder    = common.HexToAddress("0xe16359506c028e51f16be38986ec5746251e9724")
+	blockHash = common.HexToHash("0x6557ff2c1746ee6df9af644ac874e23a4ce5a39e4885d9a11b3107da8bd911f6")
+	txHash    = common.HexToHash("0x34298606ddeb907124f9e839f57632f2f1ec27db6df8e1a6b20d9ac7c03f55f2")
+)
+
+const (
+	headNumber = 19500000
+	signedTx   = "0x02f8b1012a85012a05f200850cce41660082b41194c02aaa39b223fe8d0a0e5c4f27ead9083c756cc280b844a9059cbb000000000000000000000000e16359506c028e51f16be38986ec5746251e97240000000000000000000000000000000000000000000000000de0b6b3a7640000c080a074d3a78d5b695cc5cfcc6fc807755de8f79a147f0eb48d49140f9dd9af567632a02401842c155eab745f8d524126ef6c15b83bd484f389bc44fd12fc6e8f8737e4"
+)
+
+// countingTransport counts the requests made to Etherscan.
+type countingTransport struct {
+	count int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *countingTransport) requests() int64 {
+	return atomic.LoadInt64(&t.count)
+}
+
+func newServer(t *testing.T, params rpcserver.Params) (*httptest.Server, *countingTransport) {
+	m := testbed.NewMockServer("rpcserver", false)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	transport := new(countingTransport)
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+		HTTP:    &http.Client{Transport: transport},
+	})
+
+	params.Proxy = &client.Proxy
+	params.Accounts = &client.Accounts
+	params.Logs = &client.Logs
+
+	srv := httptest.NewServer(rpcserver.New(&params))
+	t.Cleanup(srv.Close)
+
+	return srv, transport
+}
+
+func post(t *testing.T, url, body string) string {
+	rsp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	data, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestEthClient(t *testing.T) {
+	srv, _ := newServer(t, rpcserver.Params{})
+
+	client, err := ethclient.Dial(srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+
+	t.Run("ChainID", func(t *testing.T) {
+		chainID, err := client.ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1), chainID)
+	})
+
+	t.Run("BlockNumber", func(t *testing.T) {
+		number, err := client.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(headNumber), number)
+	})
+
+	t.Run("BlockByNumber", func(t *testing.T) {
+		block, err := client.BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, blockHash, block.Hash())
+		require.Len(t, block.Transactions(), 4)
+
+		for i, tx := range block.Transactions() {
+			assert.Equal(t, uint8(i), tx.Type())
+		}
+
+		assert.Equal(t, block.TxHash(), types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)))
+	})
+
+	t.Run("HeaderByNumberNotFound", func(t *testing.T) {
+		_, err := client.HeaderByNumber(ctx, big.NewInt(headNumber+1))
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+
+	t.Run("TransactionByHash", func(t *testing.T) {
+		tx, isPending, err := client.TransactionByHash(ctx, txHash)
+		require.NoError(t, err)
+		assert.False(t, isPending)
+		assert.Equal(t, txHash, tx.Hash())
+	})
+
+	t.Run("TransactionReceipt", func(t *testing.T) {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		require.NoError(t, err)
+		assert.Equal(t, txHash, receipt.TxHash)
+		assert.Equal(t, blockHash, receipt.BlockHash)
+		assert.Equal(t, types.CreateBloom(types.Receipts{receipt}), receipt.Bloom)
+	})
+
+	t.Run("BalanceAt", func(t *testing.T) {
+		balance, err := client.BalanceAt(ctx, holder, big.NewInt(headNumber))
+		require.NoError(t, err)
+		assert.Equal(t, "1250000000000000000", balance.String())
+
+		balance, err = client.BalanceAt(ctx, holder, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "1300000000000000000", balance.String())
+
+		balance, err = client.BalanceAt(ctx, holder, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		require.NoError(t, err)
+		assert.Equal(t, "1250000000000000000", balance.String())
+	})
+
+	t.Run("StorageAt", func(t *testing.T) {
+		value, err := client.StorageAt(ctx, token, common.Hash{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Wrapped Ether", string(value[:13]))
+	})
+
+	t.Run("CallContract", func(t *testing.T) {
+		data := hexutil.MustDecode("0x70a08231000000000000000000000000e16359506c028e51f16be38986ec5746251e9724")
+		result, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "27054070000000000", new(big.Int).SetBytes(result).String())
+	})
+
+	t.Run("CallContractReverted", func(t *testing.T) {
+		data := hexutil.MustDecode("0xa9059cbb000000000000000000000000e16359506c028e51f16be38986ec5746251e97240000000000000000000000000000000000000000000000056bc75e2d63100000")
+		_, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+		require.Error(t, err)
+
+		var dataErr rpc.DataError
+		require.ErrorAs(t, err, &dataErr)
+
+		revertData, ok := dataErr.ErrorData().(string)
+		require.True(t, ok)
+
+		reason, err := abi.UnpackRevert(hexutil.MustDecode(revertData))
+		require.NoError(t, err)
+		assert.Equal(t, "ERC20: transfer amount exceeds balance", reason)
+	})
+
+	t.Run("FilterLogs", func(t *testing.T) {
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: big.NewInt(headNumber),
+			ToBlock:   big.NewInt(headNumber + 10),
+			Addresses: []common.Address{token},
+			Topics: [][]common.Hash{
+				{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")},
+				{common.BytesToHash(holder.Bytes())},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, uint64(headNumber), logs[0].BlockNumber)
+	})
+
+	t.Run("SendTransaction", func(t *testing.T) {
+		tx := new(types.Transaction)
+		require.NoError(t, tx.UnmarshalBinary(hexutil.MustDecode(signedTx)))
+		require.NoError(t, client.SendTransaction(ctx, tx))
+	})
+}
+
+func TestBlockTags(t *testing.T) {
+	srv, _ := newServer(t, rpcserver.Params{})
+
+	t.Run("Finalized", func(t *testing.T) {
+		rsp := post(t, srv.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["finalized",false]}`)
+		assert.Contains(t, rsp, `"hash":"`+blockHash.Hex()+`"`)
+	})
+
+	t.Run("LogsFromEarliest", func(t *testing.T) {
+		rsp := post(t, srv.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{
+			"fromBlock":"earliest","toBlock":"0x1298bea","address":"`+token.Hex()+`",
+			"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"]
+		}]}`)
+		assert.Contains(t, rsp, `"blockNumber":"0x1298be0"`)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	srv, _ := newServer(t, rpcserver.Params{MaxBatchSize: 4})
+
+	t.Run("Calls", func(t *testing.T) {
+		rsp := post(t, srv.URL, `[
+			{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]},
+			{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"},
+			{"jsonrpc":"2.0","id":3,"method":"eth_mining","params":[]},
+			{"jsonrpc":"2.0","method":"eth_blockNumber","params":[]}
+		]`)
+
+		assert.JSONEq(t, `[
+			{"jsonrpc":"2.0","id":1,"result":"0x1"},
+			{"jsonrpc":"2.0","id":2,"result":"0x1298be0"},
+			{"jsonrpc":"2.0","id":3,"error":{"code":-32601,"message":"the method eth_mining does not exist/is not available"}}
+		]`, rsp)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		rsp := post(t, srv.URL, `[
+			{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":2,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":3,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":4,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":5,"method":"eth_chainId"}
+		]`)
+
+		assert.JSONEq(t, `{
+			"jsonrpc":"2.0","id":null,
+			"error":{"code":-32600,"message":"batch of 5 calls exceeds the limit of 4"}
+		}`, rsp)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		rsp := post(t, srv.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":[]}`)
+		assert.JSONEq(t, `{
+			"jsonrpc":"2.0","id":1,
+			"error":{"code":-32602,"message":"missing value for required argument 0"}
+		}`, rsp)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		rsp := post(t, srv.URL, `{"jsonrpc":`)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`, rsp)
+	})
+}
+
+func TestCache(t *testing.T) {
+	srv, transport := newServer(t, rpcserver.Params{CacheSize: 16, CacheTTL: time.Minute})
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["` + txHash.Hex() + `"]}`
+
+	first := post(t, srv.URL, body)
+	second := post(t, srv.URL, body)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int64(1), transport.requests())
+
+	missing := `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x1298be1",false]}`
+	for i := 0; i < 2; i++ {
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":null}`, post(t, srv.URL, missing))
+	}
+
+	assert.Equal(t, int64(3), transport.requests(), "null results should not be cached")
+
+	nonce := `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionCount","params":["` + holder.Hex() + `","pending"]}`
+	for i := 0; i < 2; i++ {
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":"0x2a"}`, post(t, srv.URL, nonce))
+	}
+
+	assert.Equal(t, int64(5), transport.requests(), "nonces should not be cached")
+}
+
+func TestRateLimit(t *testing.T) {
+	srv, transport := newServer(t, rpcserver.Params{RateLimit: 20, RateBurst: 1})
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":"0x1298be0"}`, post(t, srv.URL, body))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+	assert.Equal(t, int64(4), transport.requests())
+}
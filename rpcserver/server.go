@@ -0,0 +1,395 @@
+// Package rpcserver serves a subset of the Ethereum JSON-RPC API over HTTP by
+// translating calls to the Etherscan proxy, accounts and logs modules, so
+// that JSON-RPC tools such as cast, ethers.js or wallets can use Etherscan
+// as their provider.
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanc414/etherscan-api-go/accounts"
+	"github.com/ryanc414/etherscan-api-go/backend"
+	"github.com/ryanc414/etherscan-api-go/logs"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultChainID      = 1
+	defaultMaxBatchSize = 100
+	maxRequestBodySize  = 5 * 1024 * 1024
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+)
+
+// Params are construction parameters for the Server.
+type Params struct {
+	Proxy    *proxy.ProxyClient
+	Accounts *accounts.AccountsClient
+	Logs     *logs.LogsClient
+
+	// ChainID is returned by eth_chainId and net_version. Defaults to 1.
+	ChainID uint64
+
+	// CacheSize is the maximum number of results to cache. Results are not
+	// cached if zero.
+	CacheSize int
+
+	// CacheTTL is the duration for which results are cached. Results that
+	// depend on the latest block may be stale for up to this long. Nonces,
+	// gas prices and results for the pending block are never cached.
+	CacheTTL time.Duration
+
+	// RateLimit optionally limits the number of calls per second that are
+	// not served from the cache, which otherwise wait for their turn. Calls
+	// are not limited if zero.
+	RateLimit float64
+
+	// RateBurst is the number of calls that may be made at once under the
+	// rate limit. Defaults to 1.
+	RateBurst int
+
+	// MaxBatchSize is the maximum number of calls in a batch request.
+	// Defaults to 100.
+	MaxBatchSize int
+}
+
+// Server is an http.Handler serving JSON-RPC requests.
+type Server struct {
+	proxy        *proxy.ProxyClient
+	accounts     *accounts.AccountsClient
+	backend      *backend.Backend
+	chainID      uint64
+	methods      map[string]method
+	cache        *lru.Cache[string, cacheEntry]
+	cacheTTL     time.Duration
+	limiter      *rate.Limiter
+	maxBatchSize int
+}
+
+type cacheEntry struct {
+	result  json.RawMessage
+	expires time.Time
+}
+
+// New constructs a new Server.
+func New(params *Params) *Server {
+	s := &Server{
+		proxy:    params.Proxy,
+		accounts: params.Accounts,
+		backend: &backend.Backend{
+			Proxy:    params.Proxy,
+			Accounts: params.Accounts,
+			Logs:     params.Logs,
+		},
+		chainID:      params.ChainID,
+		cacheTTL:     params.CacheTTL,
+		maxBatchSize: params.MaxBatchSize,
+	}
+
+	if s.chainID == 0 {
+		s.chainID = defaultChainID
+	}
+
+	if s.maxBatchSize == 0 {
+		s.maxBatchSize = defaultMaxBatchSize
+	}
+
+	if params.CacheSize > 0 && params.CacheTTL > 0 {
+		s.cache = lru.NewCache[string, cacheEntry](params.CacheSize)
+	}
+
+	if params.RateLimit > 0 {
+		burst := params.RateBurst
+		if burst == 0 {
+			burst = 1
+		}
+
+		s.limiter = rate.NewLimiter(rate.Limit(params.RateLimit), burst)
+	}
+
+	s.methods = s.registerMethods()
+
+	return s
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// isNotification returns whether the request has no ID, in which case no
+// response is sent.
+func (req *request) isNotification() bool {
+	return len(req.ID) == 0
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", err.Message, err.Code)
+}
+
+// serverError converts an error from Etherscan or a node to a JSON-RPC
+// error. The code and data of JSON-RPC errors, such as the revert data of a
+// failed call, are passed through so that clients can decode them.
+func serverError(err error) *Error {
+	var codeErr rpc.Error
+	if !errors.As(err, &codeErr) {
+		return &Error{Code: codeServerError, Message: err.Error()}
+	}
+
+	rpcErr := &Error{Code: codeErr.ErrorCode(), Message: codeErr.Error()}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		rpcErr.Data = dataErr.ErrorData()
+	}
+
+	return rpcErr
+}
+
+func invalidParams(format string, args ...interface{}) *Error {
+	return &Error{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+func errorResponse(id json.RawMessage, err *Error) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+
+	return &response{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+// ServeHTTP implements http.Handler. Both single and batch requests are
+// accepted, and calls in a batch are made concurrently.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestBodySize))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	rsp := s.handleBody(req.Context(), body)
+	if rsp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rsp); err != nil {
+		log.Error().Err(err).Msg("error while writing JSON-RPC response")
+	}
+}
+
+// handleBody handles a single or batch request body, returning the value
+// to send as the response or nil if there is nothing to send.
+func (s *Server) handleBody(ctx context.Context, body []byte) interface{} {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(nil, &Error{Code: codeParseError, Message: "parse error"})
+		}
+
+		rsp := s.handleRequest(ctx, &req)
+		if req.isNotification() {
+			return nil
+		}
+
+		return rsp
+	}
+
+	var batch []request
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return errorResponse(nil, &Error{Code: codeParseError, Message: "parse error"})
+	}
+
+	if len(batch) == 0 {
+		return errorResponse(nil, &Error{Code: codeInvalidRequest, Message: "empty batch"})
+	}
+
+	if len(batch) > s.maxBatchSize {
+		return errorResponse(nil, &Error{
+			Code:    codeInvalidRequest,
+			Message: fmt.Sprintf("batch of %d calls exceeds the limit of %d", len(batch), s.maxBatchSize),
+		})
+	}
+
+	responses := make([]*response, len(batch))
+
+	var wg sync.WaitGroup
+	for i := range batch {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = s.handleRequest(ctx, &batch[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	result := make([]*response, 0, len(batch))
+	for i := range batch {
+		if !batch[i].isNotification() {
+			result = append(result, responses[i])
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func (s *Server) handleRequest(ctx context.Context, req *request) *response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, &Error{Code: codeInvalidRequest, Message: "invalid request"})
+	}
+
+	result, err := s.call(ctx, req)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = serverError(err)
+		}
+
+		return errorResponse(req.ID, rpcErr)
+	}
+
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) call(ctx context.Context, req *request) (json.RawMessage, error) {
+	m, ok := s.methods[req.Method]
+	if !ok {
+		return nil, &Error{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method),
+		}
+	}
+
+	var params []json.RawMessage
+	if len(req.Params) != 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams("params must be an array")
+		}
+	}
+
+	// Results for the pending block change with every transaction sent.
+	if isPending(params) {
+		m.cacheable = false
+	}
+
+	key := cacheKey(req.Method, params)
+	if result, ok := s.cached(key, m); ok {
+		return result, nil
+	}
+
+	if s.limiter != nil && m.upstream {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, errors.Wrap(err, "while waiting for rate limit")
+		}
+	}
+
+	value, err := m.handle(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshalling result")
+	}
+
+	s.store(key, m, result)
+
+	return result, nil
+}
+
+func cacheKey(method string, params []json.RawMessage) string {
+	var key bytes.Buffer
+	key.WriteString(method)
+
+	for _, param := range params {
+		key.WriteByte(' ')
+		if err := json.Compact(&key, param); err != nil {
+			key.Write(param)
+		}
+	}
+
+	return key.String()
+}
+
+// isPending returns whether any of the params is the pending block tag.
+func isPending(params []json.RawMessage) bool {
+	for _, param := range params {
+		if string(bytes.TrimSpace(param)) == `"pending"` {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) cached(key string, m method) (json.RawMessage, bool) {
+	if s.cache == nil || !m.cacheable {
+		return nil, false
+	}
+
+	entry, ok := s.cache.Get(key)
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+// store caches a result. Null results are not cached, as a transaction or
+// block that is not found may appear shortly.
+func (s *Server) store(key string, m method, result json.RawMessage) {
+	if s.cache == nil || !m.cacheable || string(result) == "null" {
+		return
+	}
+
+	s.cache.Add(key, cacheEntry{result: result, expires: time.Now().Add(s.cacheTTL)})
+}
@@ -0,0 +1,316 @@
+package rpcserver
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+// rpcBlock is the JSON-RPC encoding of a block. Transactions are either
+// hashes or full transactions.
+type rpcBlock struct {
+	Number           hexutil.Uint64   `json:"number"`
+	Hash             common.Hash      `json:"hash"`
+	ParentHash       common.Hash      `json:"parentHash"`
+	Nonce            types.BlockNonce `json:"nonce"`
+	MixHash          common.Hash      `json:"mixHash"`
+	SHA3Uncles       common.Hash      `json:"sha3Uncles"`
+	LogsBloom        hexutil.Bytes    `json:"logsBloom"`
+	StateRoot        common.Hash      `json:"stateRoot"`
+	Miner            common.Address   `json:"miner"`
+	Difficulty       *hexutil.Big     `json:"difficulty"`
+	TotalDifficulty  *hexutil.Big     `json:"totalDifficulty,omitempty"`
+	ExtraData        hexutil.Bytes    `json:"extraData"`
+	Size             hexutil.Uint64   `json:"size"`
+	GasLimit         *hexutil.Big     `json:"gasLimit"`
+	GasUsed          *hexutil.Big     `json:"gasUsed"`
+	Timestamp        hexutil.Uint64   `json:"timestamp"`
+	TransactionsRoot common.Hash      `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash      `json:"receiptsRoot"`
+	Transactions     interface{}      `json:"transactions"`
+	Uncles           []common.Hash    `json:"uncles"`
+
+	BaseFeePerGas         *hexutil.Big     `json:"baseFeePerGas,omitempty"`
+	WithdrawalsRoot       *common.Hash     `json:"withdrawalsRoot,omitempty"`
+	Withdrawals           []*rpcWithdrawal `json:"withdrawals,omitempty"`
+	BlobGasUsed           *hexutil.Uint64  `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas         *hexutil.Uint64  `json:"excessBlobGas,omitempty"`
+	ParentBeaconBlockRoot *common.Hash     `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash          *common.Hash     `json:"requestsHash,omitempty"`
+}
+
+type rpcWithdrawal struct {
+	Index          hexutil.Uint64 `json:"index"`
+	ValidatorIndex hexutil.Uint64 `json:"validatorIndex"`
+	Address        common.Address `json:"address"`
+	Amount         hexutil.Uint64 `json:"amount"`
+}
+
+func newRPCBlock(
+	b *proxy.ProxyBaseBlockInfo, totalDifficulty *big.Int, txs interface{},
+) *rpcBlock {
+	block := &rpcBlock{
+		Number:           hexutil.Uint64(b.Number),
+		Hash:             b.Hash,
+		ParentHash:       b.ParentHash,
+		Nonce:            types.EncodeNonce(uint64OrZero(b.Nonce)),
+		MixHash:          b.MixHash,
+		SHA3Uncles:       b.SHA3Uncles,
+		LogsBloom:        b.LogsBloom,
+		StateRoot:        b.StateRoot,
+		Miner:            b.Miner,
+		Difficulty:       bigOrZero(b.Difficulty),
+		TotalDifficulty:  (*hexutil.Big)(totalDifficulty),
+		ExtraData:        b.ExtraData,
+		Size:             hexutil.Uint64(b.Size),
+		GasLimit:         bigOrZero(b.GasLimit),
+		GasUsed:          bigOrZero(b.GasUsed),
+		Timestamp:        hexutil.Uint64(b.Timestamp.Unix()),
+		TransactionsRoot: b.TransactionsRoot,
+		ReceiptsRoot:     b.ReceiptsRoot,
+		Transactions:     txs,
+		Uncles:           b.Uncles,
+		BaseFeePerGas:    (*hexutil.Big)(b.BaseFeePerGas),
+	}
+
+	if block.Uncles == nil {
+		block.Uncles = []common.Hash{}
+	}
+
+	// As with headers, the fields of each upgrade are present according to
+	// whether the block includes it.
+	if b.WithdrawalsRoot != (common.Hash{}) {
+		withdrawalsRoot := b.WithdrawalsRoot
+		block.WithdrawalsRoot = &withdrawalsRoot
+
+		block.Withdrawals = make([]*rpcWithdrawal, len(b.Withdrawals))
+		for i, w := range b.Withdrawals {
+			block.Withdrawals[i] = &rpcWithdrawal{
+				Index:          hexutil.Uint64(w.Index),
+				ValidatorIndex: hexutil.Uint64(w.ValidatorIndex),
+				Address:        w.Address,
+				Amount:         hexutil.Uint64(w.Amount),
+			}
+		}
+	}
+
+	if b.ParentBeaconBlockRoot != (common.Hash{}) {
+		blobGasUsed := hexutil.Uint64(b.BlobGasUsed)
+		excessBlobGas := hexutil.Uint64(b.ExcessBlobGas)
+		parentBeaconRoot := b.ParentBeaconBlockRoot
+
+		block.BlobGasUsed = &blobGasUsed
+		block.ExcessBlobGas = &excessBlobGas
+		block.ParentBeaconBlockRoot = &parentBeaconRoot
+	}
+
+	if b.RequestsHash != (common.Hash{}) {
+		requestsHash := b.RequestsHash
+		block.RequestsHash = &requestsHash
+	}
+
+	return block
+}
+
+// rpcTransaction is the JSON-RPC encoding of a transaction. The block
+// fields are null for pending transactions.
+type rpcTransaction struct {
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             common.Address  `json:"from"`
+	Gas              *hexutil.Big    `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             common.Hash     `json:"hash"`
+	Input            hexutil.Bytes   `json:"input"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	To               *common.Address `json:"to"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	Value            *hexutil.Big    `json:"value"`
+	Type             hexutil.Uint64  `json:"type"`
+	V                hexutil.Uint64  `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+
+	ChainID              *hexutil.Big       `json:"chainId,omitempty"`
+	AccessList           *types.AccessList  `json:"accessList,omitempty"`
+	YParity              *hexutil.Uint64    `json:"yParity,omitempty"`
+	MaxFeePerGas         *hexutil.Big       `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big       `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerBlobGas     *hexutil.Big       `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []common.Hash      `json:"blobVersionedHashes,omitempty"`
+	AuthorizationList    []rpcAuthorization `json:"authorizationList,omitempty"`
+}
+
+type rpcAuthorization struct {
+	ChainID *hexutil.Big   `json:"chainId"`
+	Address common.Address `json:"address"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+	YParity hexutil.Uint64 `json:"yParity"`
+	R       *hexutil.Big   `json:"r"`
+	S       *hexutil.Big   `json:"s"`
+}
+
+func newRPCTransaction(tx *proxy.ProxyTransactionInfo) *rpcTransaction {
+	result := &rpcTransaction{
+		From:                 tx.From,
+		Gas:                  bigOrZero(tx.Gas),
+		GasPrice:             (*hexutil.Big)(tx.GasPrice),
+		Hash:                 tx.Hash,
+		Input:                tx.Input,
+		Nonce:                hexutil.Uint64(tx.Nonce),
+		To:                   optionalAddress(tx.To),
+		Value:                bigOrZero(tx.Value),
+		Type:                 hexutil.Uint64(tx.Type),
+		V:                    hexutil.Uint64(tx.V),
+		R:                    bigOrZero(tx.R),
+		S:                    bigOrZero(tx.S),
+		ChainID:              (*hexutil.Big)(tx.ChainID),
+		MaxFeePerGas:         (*hexutil.Big)(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     (*hexutil.Big)(tx.MaxFeePerBlobGas),
+		BlobVersionedHashes:  tx.BlobVersionedHashes,
+	}
+
+	if tx.BlockHash != (common.Hash{}) {
+		blockHash := tx.BlockHash
+		index := hexutil.Uint64(tx.TransactionIndex)
+
+		result.BlockHash = &blockHash
+		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(tx.BlockNumber))
+		result.TransactionIndex = &index
+	}
+
+	// Typed transactions have an access list and a y-parity, which is the
+	// same as V.
+	if tx.Type != types.LegacyTxType {
+		accessList := make(types.AccessList, len(tx.AccessList))
+		for i := range tx.AccessList {
+			accessList[i] = types.AccessTuple{
+				Address:     tx.AccessList[i].Address,
+				StorageKeys: tx.AccessList[i].StorageKeys,
+			}
+		}
+
+		yParity := hexutil.Uint64(tx.V)
+
+		result.AccessList = &accessList
+		result.YParity = &yParity
+	}
+
+	for _, auth := range tx.AuthorizationList {
+		result.AuthorizationList = append(result.AuthorizationList, rpcAuthorization{
+			ChainID: bigOrZero(auth.ChainID),
+			Address: auth.Address,
+			Nonce:   hexutil.Uint64(auth.Nonce),
+			YParity: hexutil.Uint64(auth.YParity),
+			R:       bigOrZero(auth.R),
+			S:       bigOrZero(auth.S),
+		})
+	}
+
+	return result
+}
+
+// rpcReceipt is the JSON-RPC encoding of a transaction receipt.
+type rpcReceipt struct {
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	CumulativeGasUsed *hexutil.Big    `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	From              common.Address  `json:"from"`
+	GasUsed           *hexutil.Big    `json:"gasUsed"`
+	Logs              []*types.Log    `json:"logs"`
+	LogsBloom         hexutil.Bytes   `json:"logsBloom"`
+	To                *common.Address `json:"to"`
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	Type              hexutil.Uint64  `json:"type"`
+
+	// Status is replaced by Root in receipts from before the Byzantium
+	// upgrade.
+	Status *hexutil.Uint64 `json:"status,omitempty"`
+	Root   hexutil.Bytes   `json:"root,omitempty"`
+
+	BlobGasUsed  *hexutil.Uint64 `json:"blobGasUsed,omitempty"`
+	BlobGasPrice *hexutil.Big    `json:"blobGasPrice,omitempty"`
+}
+
+func newRPCReceipt(r *proxy.ProxyTransactionReceipt) *rpcReceipt {
+	receipt := &rpcReceipt{
+		BlockHash:         r.BlockHash,
+		BlockNumber:       hexutil.Uint64(r.BlockNumber),
+		ContractAddress:   r.ContractAddress,
+		CumulativeGasUsed: bigOrZero(r.CumulativeGasUsed),
+		EffectiveGasPrice: (*hexutil.Big)(r.EffectiveGasPrice),
+		From:              r.From,
+		GasUsed:           bigOrZero(r.GasUsed),
+		Logs:              make([]*types.Log, len(r.Logs)),
+		LogsBloom:         r.LogsBloom,
+		To:                optionalAddress(r.To),
+		TransactionHash:   r.TransactionHash,
+		TransactionIndex:  hexutil.Uint64(r.TransactionIndex),
+		Type:              hexutil.Uint64(r.Type),
+		Root:              r.Root,
+		BlobGasPrice:      (*hexutil.Big)(r.BlobGasPrice),
+	}
+
+	if len(r.Root) == 0 {
+		var status hexutil.Uint64
+		if r.Status {
+			status = 1
+		}
+
+		receipt.Status = &status
+	}
+
+	if r.BlobGasUsed != 0 {
+		blobGasUsed := hexutil.Uint64(r.BlobGasUsed)
+		receipt.BlobGasUsed = &blobGasUsed
+	}
+
+	for i, l := range r.Logs {
+		receipt.Logs[i] = &types.Log{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TransactionHash,
+			TxIndex:     uint(l.TransactionIndex),
+			BlockHash:   l.BlockHash,
+			Index:       uint(l.LogIndex),
+			Removed:     l.Removed,
+		}
+	}
+
+	return receipt
+}
+
+// optionalAddress returns nil for the zero address, which as the recipient
+// of a transaction denotes contract creation.
+func optionalAddress(addr common.Address) *common.Address {
+	if addr == (common.Address{}) {
+		return nil
+	}
+
+	return &addr
+}
+
+func bigOrZero(val *big.Int) *hexutil.Big {
+	if val == nil {
+		return new(hexutil.Big)
+	}
+
+	return (*hexutil.Big)(val)
+}
+
+func uint64OrZero(val *big.Int) uint64 {
+	if val == nil {
+		return 0
+	}
+
+	return val.Uint64()
+}
@@ -32,6 +32,32 @@ func (c *StatsClient) GetTotalETHSupply(ctx context.Context) (*big.Int, error) {
 	return result.Unwrap(), nil
 }
 
+// ETHSupplyBreakdown describes the amount of Ether in circulation along with
+// the components of issuance since EIP-1559 and the merge. All amounts are
+// in wei.
+type ETHSupplyBreakdown struct {
+	ETHSupply      *big.Int `etherscan:"EthSupply"`
+	ETH2Staking    *big.Int `etherscan:"Eth2Staking"`
+	BurntFees      *big.Int `etherscan:"BurntFees"`
+	WithdrawnTotal *big.Int `etherscan:"WithdrawnTotal"`
+}
+
+// GetETHSupplyBreakdown returns the current amount of Ether in circulation,
+// ETH2 staking rewards, burnt fees and total withdrawn Ether.
+func (c *StatsClient) GetETHSupplyBreakdown(ctx context.Context) (*ETHSupplyBreakdown, error) {
+	result := new(ETHSupplyBreakdown)
+	err := c.API.Call(ctx, &httpapi.CallParams{
+		Module: ecommon.StatsModule,
+		Action: "ethsupply2",
+		Result: result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // ETHPrice describes the price of Ether at a particular time.
 type ETHPrice struct {
 	ETHBTC          decimal.Decimal
@@ -34,6 +34,12 @@ func TestStats(t *testing.T) {
 		assert.Equal(t, "116487067186500000000000000", supply.String())
 	})
 
+	t.Run("GetETHSupplyBreakdown", func(t *testing.T) {
+		supply, err := client.Stats.GetETHSupplyBreakdown(ctx)
+		require.NoError(t, err)
+		cupaloy.SnapshotT(t, supply)
+	})
+
 	t.Run("GetLastETHPrice", func(t *testing.T) {
 		price, err := client.Stats.GetLastETHPrice(ctx)
 		require.NoError(t, err)
@@ -0,0 +1,161 @@
+// Package storage computes contract storage slots following the Solidity
+// storage layout, and reads and decodes their values via the proxy module.
+package storage
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/proxy"
+)
+
+// Well-known proxy storage slots.
+var (
+	// EIP1967ImplementationSlot holds the implementation address of an
+	// EIP-1967 proxy: keccak256("eip1967.proxy.implementation") - 1.
+	EIP1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+	// EIP1967AdminSlot holds the admin address of an EIP-1967 proxy:
+	// keccak256("eip1967.proxy.admin") - 1.
+	EIP1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+
+	// EIP1967BeaconSlot holds the beacon address of an EIP-1967 beacon
+	// proxy: keccak256("eip1967.proxy.beacon") - 1.
+	EIP1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+
+	// EIP1822ProxiableSlot holds the implementation address of an EIP-1822
+	// (UUPS) proxy: keccak256("PROXIABLE").
+	EIP1822ProxiableSlot = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+
+	// OZLegacyImplementationSlot holds the implementation address of a proxy
+	// from the legacy OpenZeppelin (zos) SDK:
+	// keccak256("org.zeppelinos.proxy.implementation").
+	OZLegacyImplementationSlot = common.HexToHash("0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3")
+)
+
+// Slot returns the slot of a state variable declared at a position.
+func Slot(position uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(position))
+}
+
+// AddressKey encodes an address as a mapping key.
+func AddressKey(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+// UintKey encodes an unsigned integer as a mapping key.
+func UintKey(key *big.Int) common.Hash {
+	return common.BigToHash(key)
+}
+
+// MappingSlot returns the slot of the value stored under a key in a mapping
+// declared at slot. Value-type keys must be left-padded to 32 bytes, as done
+// by AddressKey and UintKey. Further keys index nested mappings.
+func MappingSlot(slot common.Hash, key common.Hash, nested ...common.Hash) common.Hash {
+	slot = crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+	for _, key := range nested {
+		slot = crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+	}
+
+	return slot
+}
+
+// BytesMappingSlot returns the slot of the value stored under a string or
+// bytes key in a mapping declared at slot. Such keys are not padded.
+func BytesMappingSlot(slot common.Hash, key []byte) common.Hash {
+	return crypto.Keccak256Hash(key, slot.Bytes())
+}
+
+// ArrayElementSlot returns the slot of an element of a dynamic array
+// declared at slot, whose elements each occupy elemSlots slots.
+func ArrayElementSlot(slot common.Hash, index *big.Int, elemSlots uint64) common.Hash {
+	start := crypto.Keccak256Hash(slot.Bytes()).Big()
+	offset := new(big.Int).Mul(index, new(big.Int).SetUint64(elemSlots))
+
+	return wrap(start.Add(start, offset))
+}
+
+// OffsetSlot returns the slot of a struct member at an offset from the slot
+// at which the struct starts.
+func OffsetSlot(slot common.Hash, offset uint64) common.Hash {
+	sum := new(big.Int).Add(slot.Big(), new(big.Int).SetUint64(offset))
+	return wrap(sum)
+}
+
+var slotModulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// wrap reduces a slot number modulo 2^256, as slot arithmetic overflows in
+// the EVM.
+func wrap(slot *big.Int) common.Hash {
+	return common.BigToHash(slot.Mod(slot, slotModulus))
+}
+
+// DecodeAddress decodes a storage value holding an address.
+func DecodeAddress(value []byte) common.Address {
+	return common.BytesToAddress(value)
+}
+
+// DecodeUint decodes a storage value holding an unsigned integer.
+func DecodeUint(value []byte) *big.Int {
+	return new(big.Int).SetBytes(value)
+}
+
+// DecodeBool decodes a storage value holding a bool.
+func DecodeBool(value []byte) bool {
+	return DecodeUint(value).Sign() != 0
+}
+
+// Reader reads and decodes contract storage values.
+type Reader struct {
+	Proxy *proxy.ProxyClient
+}
+
+// Read returns the raw value stored in a slot of a contract.
+func (r *Reader) Read(
+	ctx context.Context, contract common.Address, slot common.Hash, tag ecommon.BlockTag,
+) ([]byte, error) {
+	return r.Proxy.GetStorageAt(ctx, &proxy.GetStorageRequest{
+		Address:  contract,
+		Position: slot,
+		Tag:      tag,
+	})
+}
+
+// ReadAddress returns the address stored in a slot of a contract.
+func (r *Reader) ReadAddress(
+	ctx context.Context, contract common.Address, slot common.Hash, tag ecommon.BlockTag,
+) (common.Address, error) {
+	value, err := r.Read(ctx, contract, slot, tag)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return DecodeAddress(value), nil
+}
+
+// ReadUint returns the unsigned integer stored in a slot of a contract.
+func (r *Reader) ReadUint(
+	ctx context.Context, contract common.Address, slot common.Hash, tag ecommon.BlockTag,
+) (*big.Int, error) {
+	value, err := r.Read(ctx, contract, slot, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeUint(value), nil
+}
+
+// ReadBool returns the bool stored in a slot of a contract.
+func (r *Reader) ReadBool(
+	ctx context.Context, contract common.Address, slot common.Hash, tag ecommon.BlockTag,
+) (bool, error) {
+	value, err := r.Read(ctx, contract, slot, tag)
+	if err != nil {
+		return false, err
+	}
+
+	return DecodeBool(value), nil
+}
@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ryanc414/etherscan-api-go"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
+	"github.com/ryanc414/etherscan-api-go/storage"
+	"github.com/ryanc414/etherscan-api-go/testbed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlots(t *testing.T) {
+	t.Run("Mapping", func(t *testing.T) {
+		slot := storage.MappingSlot(storage.Slot(0), storage.UintKey(big.NewInt(0)))
+		expected := common.HexToHash("0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5")
+		assert.Equal(t, expected, slot)
+	})
+
+	t.Run("NestedMapping", func(t *testing.T) {
+		owner := storage.AddressKey(common.HexToAddress("0x1"))
+		spender := storage.AddressKey(common.HexToAddress("0x2"))
+
+		slot := storage.MappingSlot(storage.Slot(1), owner, spender)
+		expected := storage.MappingSlot(storage.MappingSlot(storage.Slot(1), owner), spender)
+		assert.Equal(t, expected, slot)
+	})
+
+	t.Run("ArrayElement", func(t *testing.T) {
+		slot := storage.ArrayElementSlot(storage.Slot(0), big.NewInt(2), 2)
+		expected := common.HexToHash("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e567")
+		assert.Equal(t, expected, slot)
+	})
+
+	t.Run("OffsetOverflow", func(t *testing.T) {
+		max := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+		assert.Equal(t, storage.Slot(1), storage.OffsetSlot(max, 2))
+	})
+}
+
+func TestReader(t *testing.T) {
+	m := testbed.NewMockServer("proxy", true)
+	t.Cleanup(m.Close)
+
+	u, err := m.URL()
+	require.NoError(t, err)
+
+	client := etherscan.New(&etherscan.Params{
+		APIKey:  m.APIKey,
+		BaseURL: u,
+	})
+	reader := storage.Reader{Proxy: &client.Proxy}
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419")
+	latest := ecommon.BlockTagNamed(ecommon.BlockParameterLatest)
+
+	t.Run("ReadAddress", func(t *testing.T) {
+		impl, err := reader.ReadAddress(ctx, contract, storage.EIP1967ImplementationSlot, latest)
+		require.NoError(t, err)
+
+		expected := common.HexToAddress("0xa2327a938febf5fec13bacfb16ae10ecbc4cbdcf")
+		assert.Equal(t, expected, impl)
+	})
+
+	t.Run("ReadUint", func(t *testing.T) {
+		slot := storage.MappingSlot(storage.Slot(0), storage.UintKey(big.NewInt(0)))
+		val, err := reader.ReadUint(ctx, contract, slot, ecommon.BlockTagNumber(1000000))
+		require.NoError(t, err)
+		assert.Equal(t, "10000000000", val.String())
+	})
+
+	t.Run("ReadBool", func(t *testing.T) {
+		slot := storage.ArrayElementSlot(storage.Slot(0), big.NewInt(0), 1)
+		val, err := reader.ReadBool(ctx, contract, slot, latest)
+		require.NoError(t, err)
+		assert.True(t, val)
+	})
+}
@@ -0,0 +1,178 @@
+package testbed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ryanc414/purehttp"
+)
+
+// NodeHandler handles a JSON-RPC method call on a MockNode. The returned
+// result is encoded as JSON; a json.RawMessage is sent verbatim.
+type NodeHandler func(params []json.RawMessage) (interface{}, error)
+
+// MockNode is a minimal JSON-RPC server that stands in for an Ethereum node.
+type MockNode struct {
+	mu       sync.Mutex
+	handlers map[string]NodeHandler
+	calls    map[string]int
+	srv      *httptest.Server
+}
+
+func NewMockNode() *MockNode {
+	n := &MockNode{
+		handlers: make(map[string]NodeHandler),
+		calls:    make(map[string]int),
+	}
+
+	h := purehttp.NewHandler(n.handleRequest)
+	n.srv = httptest.NewServer(h)
+
+	return n
+}
+
+func (n *MockNode) Close() {
+	n.srv.Close()
+}
+
+func (n *MockNode) URL() string {
+	return n.srv.URL
+}
+
+// Handle registers a handler for a JSON-RPC method.
+func (n *MockNode) Handle(method string, handler NodeHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.handlers[method] = handler
+}
+
+// Calls returns the number of times a method has been called.
+func (n *MockNode) Calls(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls[method]
+}
+
+// NodeError is an error with a JSON-RPC error code, which handlers may
+// return instead of a plain error to set a code other than -32000.
+type NodeError struct {
+	Code    int
+	Message string
+}
+
+func (err *NodeError) Error() string {
+	return err.Message
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func (n *MockNode) handleRequest(req *http.Request) (*purehttp.Response, error) {
+	if req.Method != http.MethodPost {
+		return &purehttp.Response{
+			Body:       []byte("method not allowed\n"),
+			StatusCode: http.StatusMethodNotAllowed,
+		}, nil
+	}
+
+	var rpcReq rpcRequest
+	if err := json.NewDecoder(req.Body).Decode(&rpcReq); err != nil {
+		return &purehttp.Response{
+			Body:       []byte(fmt.Sprintf("invalid request: %v\n", err)),
+			StatusCode: http.StatusBadRequest,
+		}, nil
+	}
+
+	rsp := n.handleCall(&rpcReq)
+
+	body, err := json.Marshal(rsp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &purehttp.Response{
+		Body:       body,
+		JSON:       true,
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func (n *MockNode) handleCall(req *rpcRequest) *rpcResponse {
+	rsp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+
+	n.mu.Lock()
+	handler, ok := n.handlers[req.Method]
+	n.calls[req.Method]++
+	n.mu.Unlock()
+
+	if !ok {
+		rsp.Error = &rpcError{
+			Code:    -32601,
+			Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method),
+		}
+		return &rsp
+	}
+
+	result, err := handler(req.Params)
+	if err != nil {
+		rsp.Error = &rpcError{Code: -32000, Message: err.Error()}
+
+		var nodeErr *NodeError
+		if errors.As(err, &nodeErr) {
+			rsp.Error.Code = nodeErr.Code
+		}
+
+		return &rsp
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+
+	rsp.Result = result
+	return &rsp
+}
+
+// FixtureResult returns the result field of a response stored in a test
+// data file, for serving the same data from a MockNode.
+func (m *MockServer) FixtureResult(action, query string) (json.RawMessage, error) {
+	responses, err := m.loadResponses(action)
+	if err != nil {
+		return nil, err
+	}
+
+	rspData, ok := responses[query]
+	if !ok {
+		return nil, fmt.Errorf("query path not found: %s", query)
+	}
+
+	var rsp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(rspData, &rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp.Result, nil
+}
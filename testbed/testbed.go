@@ -84,17 +84,11 @@ func (m *MockServer) handleAction(q url.Values) (*purehttp.Response, error) {
 
 	params := m.filterQuery(q)
 
-	responsePath := path.Join(m.testDir, fmt.Sprintf("%s.json", action))
-	data, err := ioutil.ReadFile(responsePath)
+	responses, err := m.loadResponses(action)
 	if err != nil {
 		return nil, err
 	}
 
-	responses := make(map[string]json.RawMessage)
-	if err := json.Unmarshal(data, &responses); err != nil {
-		return nil, err
-	}
-
 	encoded := params.Encode()
 	rspData, ok := responses[encoded]
 	if !ok {
@@ -110,6 +104,21 @@ func (m *MockServer) handleAction(q url.Values) (*purehttp.Response, error) {
 	}, nil
 }
 
+func (m *MockServer) loadResponses(action string) (map[string]json.RawMessage, error) {
+	responsePath := path.Join(m.testDir, fmt.Sprintf("%s.json", action))
+	data, err := ioutil.ReadFile(responsePath)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
 func (m *MockServer) filterQuery(q url.Values) url.Values {
 	params := make(url.Values, len(q))
 
@@ -134,6 +134,9 @@ type TokenInfo struct {
 	Discord         string
 	Whitepaper      string
 	TokenPriceUSD   decimal.Decimal `etherscan:"tokenPriceUSD"`
+
+	// Extra holds any response fields not decoded into the fields above.
+	Extra ecommon.ExtraFields `etherscan:",extra"`
 }
 
 // GetTokenInfo returns project information and social media links of an ERC-20/ERC-721 token.
@@ -153,3 +156,215 @@ func (c *TokensClient) GetTokenInfo(
 
 	return result, nil
 }
+
+// TokenHolderListRequest contains the request parameters for GetTokenHolderList.
+type TokenHolderListRequest struct {
+	ContractAddress common.Address
+	ecommon.Pagination
+}
+
+// TokenHolder describes the balance of a token held by an address.
+type TokenHolder struct {
+	Address  common.Address `etherscan:"TokenHolderAddress"`
+	Quantity *big.Int       `etherscan:"TokenHolderQuantity"`
+}
+
+// GetTokenHolderList returns a page of the current holders of an ERC-20 token
+// and their balances.
+func (c *TokensClient) GetTokenHolderList(
+	ctx context.Context, req *TokenHolderListRequest,
+) (result []TokenHolder, err error) {
+	err = c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.TokenModule,
+		Action:  "tokenholderlist",
+		Request: req,
+		Result:  &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetTokenHolderCount returns the number of addresses holding an ERC-20 token.
+func (c *TokensClient) GetTokenHolderCount(
+	ctx context.Context, contractAddr common.Address,
+) (uint64, error) {
+	req := struct{ ContractAddress common.Address }{contractAddr}
+	var result marshallers.UintStr
+
+	err := c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.TokenModule,
+		Action:  "tokenholdercount",
+		Request: req,
+		Result:  &result,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Unwrap(), nil
+}
+
+// TokenHolderIterator iterates over all holders of a token, fetching one
+// page of holders at a time.
+type TokenHolderIterator struct {
+	client *TokensClient
+	req    TokenHolderListRequest
+	page   []TokenHolder
+	pos    int
+	done   bool
+	err    error
+}
+
+// IterateTokenHolders returns an iterator over all holders of an ERC-20
+// token, requesting pageSize holders per call.
+func (c *TokensClient) IterateTokenHolders(
+	contractAddr common.Address, pageSize uint64,
+) *TokenHolderIterator {
+	return &TokenHolderIterator{
+		client: c,
+		req: TokenHolderListRequest{
+			ContractAddress: contractAddr,
+			Pagination:      ecommon.Pagination{Page: 0, Offset: pageSize},
+		},
+	}
+}
+
+// Next advances the iterator to the next holder, fetching the next page if
+// required. It returns false when there are no more holders or an error
+// occurred.
+func (it *TokenHolderIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	it.req.Page++
+	page, err := it.client.GetTokenHolderList(ctx, &it.req)
+	if httpapi.IsEmptyResult(err) {
+		it.done = true
+		return false
+	}
+
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = page
+	it.pos = 0
+	it.done = uint64(len(page)) < it.req.Offset
+
+	return len(page) > 0
+}
+
+// Holder returns the current holder.
+func (it *TokenHolderIterator) Holder() TokenHolder {
+	return it.page[it.pos]
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *TokenHolderIterator) Err() error {
+	return it.err
+}
+
+// AddressTokensRequest contains the request parameters for
+// GetAddressTokenBalances and GetAddressNFTBalances.
+type AddressTokensRequest struct {
+	Address common.Address
+	ecommon.Pagination
+}
+
+// AddressTokenBalance describes the balance of an ERC-20 token held by an address.
+type AddressTokenBalance struct {
+	ContractAddress common.Address `etherscan:"TokenAddress"`
+	Name            string         `etherscan:"TokenName"`
+	Symbol          string         `etherscan:"TokenSymbol"`
+	Decimals        uint32         `etherscan:"TokenDivisor"`
+	Quantity        *big.Int       `etherscan:"TokenQuantity"`
+}
+
+// GetAddressTokenBalances returns the balances of all ERC-20 tokens held by an address.
+func (c *TokensClient) GetAddressTokenBalances(
+	ctx context.Context, req *AddressTokensRequest,
+) (result []AddressTokenBalance, err error) {
+	err = c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.AccountsModule,
+		Action:  "addresstokenbalance",
+		Request: req,
+		Result:  &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddressNFTBalance describes the number of tokens of an ERC-721 collection
+// held by an address.
+type AddressNFTBalance struct {
+	ContractAddress common.Address `etherscan:"TokenAddress"`
+	Name            string         `etherscan:"TokenName"`
+	Symbol          string         `etherscan:"TokenSymbol"`
+	Quantity        *big.Int       `etherscan:"TokenQuantity"`
+}
+
+// GetAddressNFTBalances returns the ERC-721 collections held by an address
+// and the number of tokens held in each.
+func (c *TokensClient) GetAddressNFTBalances(
+	ctx context.Context, req *AddressTokensRequest,
+) (result []AddressNFTBalance, err error) {
+	err = c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.AccountsModule,
+		Action:  "addresstokennftbalance",
+		Request: req,
+		Result:  &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// NFTInventoryRequest contains the request parameters for GetAddressNFTInventory.
+type NFTInventoryRequest struct {
+	Address         common.Address
+	ContractAddress common.Address
+	ecommon.Pagination
+}
+
+// NFTInventoryItem identifies a single ERC-721 token held by an address.
+type NFTInventoryItem struct {
+	ContractAddress common.Address `etherscan:"TokenAddress"`
+	TokenID         *big.Int       `etherscan:"TokenId"`
+}
+
+// GetAddressNFTInventory returns the ERC-721 token IDs of a collection held
+// by an address.
+func (c *TokensClient) GetAddressNFTInventory(
+	ctx context.Context, req *NFTInventoryRequest,
+) (result []NFTInventoryItem, err error) {
+	err = c.API.Call(ctx, &httpapi.CallParams{
+		Module:  ecommon.AccountsModule,
+		Action:  "addresstokennftinventory",
+		Request: req,
+		Result:  &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
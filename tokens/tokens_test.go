@@ -8,6 +8,7 @@ import (
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ryanc414/etherscan-api-go"
+	ecommon "github.com/ryanc414/etherscan-api-go/common"
 	"github.com/ryanc414/etherscan-api-go/testbed"
 	"github.com/ryanc414/etherscan-api-go/tokens"
 	"github.com/stretchr/testify/assert"
@@ -71,4 +72,69 @@ func TestToken(t *testing.T) {
 
 		cupaloy.SnapshotT(t, result)
 	})
+
+	holdersContract := common.HexToAddress("0xaaaebe6fe48e54f431b0c390cfaf0b017d09d42d")
+
+	t.Run("GetTokenHolderList", func(t *testing.T) {
+		result, err := client.Tokens.GetTokenHolderList(ctx, &tokens.TokenHolderListRequest{
+			ContractAddress: holdersContract,
+			Pagination:      ecommon.Pagination{Page: 1, Offset: 2},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		cupaloy.SnapshotT(t, result)
+	})
+
+	t.Run("GetTokenHolderCount", func(t *testing.T) {
+		result, err := client.Tokens.GetTokenHolderCount(ctx, holdersContract)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), result)
+	})
+
+	t.Run("IterateTokenHolders", func(t *testing.T) {
+		it := client.Tokens.IterateTokenHolders(holdersContract, 2)
+
+		var holders []tokens.TokenHolder
+		for it.Next(ctx) {
+			holders = append(holders, it.Holder())
+		}
+		require.NoError(t, it.Err())
+		require.Len(t, holders, 4)
+		assert.Equal(t, "24576000000000000000000", holders[3].Quantity.String())
+	})
+
+	t.Run("GetAddressTokenBalances", func(t *testing.T) {
+		result, err := client.Tokens.GetAddressTokenBalances(ctx, &tokens.AddressTokensRequest{
+			Address:    common.HexToAddress("0x983e3660c0bE01991785F80f266A84B911ab59b0"),
+			Pagination: ecommon.Pagination{Page: 1, Offset: 100},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		cupaloy.SnapshotT(t, result)
+	})
+
+	t.Run("GetAddressNFTBalances", func(t *testing.T) {
+		result, err := client.Tokens.GetAddressNFTBalances(ctx, &tokens.AddressTokensRequest{
+			Address:    common.HexToAddress("0x6b52e83941eb10f9c613c395a834457559a80114"),
+			Pagination: ecommon.Pagination{Page: 1, Offset: 100},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		cupaloy.SnapshotT(t, result)
+	})
+
+	t.Run("GetAddressNFTInventory", func(t *testing.T) {
+		result, err := client.Tokens.GetAddressNFTInventory(ctx, &tokens.NFTInventoryRequest{
+			Address:         common.HexToAddress("0x123432244443b54409430979df8333f9308a6040"),
+			ContractAddress: common.HexToAddress("0xed5af388653567af2f388e6224dc7c4b3241c544"),
+			Pagination:      ecommon.Pagination{Page: 1, Offset: 100},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		cupaloy.SnapshotT(t, result)
+	})
 }